@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// analyticsPartitionMonths is how many monthly partitions, starting with the
+// current month, are kept created ahead of time.
+const analyticsPartitionMonths = 2
+
+// analyticsSummaryDays is the default window of the summary endpoint.
+const analyticsSummaryDays = 30
+
+type analyticsEventPayload struct {
+	Type         string `json:"type" validate:"required,oneof=page_view click"`
+	Path         string `json:"path" validate:"required,startswith=/,max=2048"`
+	Referrer     string `json:"referrer" validate:"omitempty,url,max=2048"`
+	UTMSource    string `json:"utm_source" validate:"max=255"`
+	UTMMedium    string `json:"utm_medium" validate:"max=255"`
+	UTMCampaign  string `json:"utm_campaign" validate:"max=255"`
+	UTMTerm      string `json:"utm_term" validate:"max=255"`
+	UTMContent   string `json:"utm_content" validate:"max=255"`
+	ResourceType string `json:"resource_type" validate:"required_with=ResourceID,omitempty,oneof=experience"`
+	ResourceID   int64  `json:"resource_id" validate:"required_with=ResourceType,omitempty,gt=0"`
+}
+
+// recordAnalyticsEventHandler accepts a page view or click from the
+// frontend. Visitors that send Do Not Track or Global Privacy Control are
+// acknowledged but not recorded, and the IP address is never stored: it
+// only feeds the daily visitor hash.
+func (app *application) recordAnalyticsEventHandler(w http.ResponseWriter, r *http.Request) {
+	var payload analyticsEventPayload
+
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if r.Header.Get("DNT") == "1" || r.Header.Get("Sec-GPC") == "1" {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	visitor, err := app.visitors.Hash(r.Context(), clientIP(r), r.UserAgent())
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	event := &store.AnalyticsEvent{
+		OccurredAt:   time.Now(),
+		Type:         payload.Type,
+		Path:         payload.Path,
+		Referrer:     stripQuery(payload.Referrer),
+		UTMSource:    payload.UTMSource,
+		UTMMedium:    payload.UTMMedium,
+		UTMCampaign:  payload.UTMCampaign,
+		UTMTerm:      payload.UTMTerm,
+		UTMContent:   payload.UTMContent,
+		ResourceType: payload.ResourceType,
+		ResourceID:   payload.ResourceID,
+		VisitorHash:  visitor,
+	}
+
+	if !app.analytics.Enqueue(event) {
+		app.requestLogger(r).Warnw("analytics buffer full, dropping event", "path", payload.Path)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// analyticsSummaryHandler aggregates events between the ?from and ?to
+// dates (YYYY-MM-DD, both inclusive). It defaults to the last 30 days.
+func (app *application) analyticsSummaryHandler(w http.ResponseWriter, r *http.Request) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to := today.AddDate(0, 0, -(analyticsSummaryDays-1)), today
+
+	q := r.URL.Query()
+	if v := q.Get("from"); v != "" {
+		d, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			app.badRequestResponse(w, r, errors.New("from must be a date such as 2024-01-31"))
+			return
+		}
+		from = d
+	}
+	if v := q.Get("to"); v != "" {
+		d, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			app.badRequestResponse(w, r, errors.New("to must be a date such as 2024-01-31"))
+			return
+		}
+		to = d
+	}
+
+	if to.Before(from) {
+		app.badRequestResponse(w, r, errors.New("to must not be before from"))
+		return
+	}
+
+	summary, err := app.store.Analytics.Summary(r.Context(), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, summary); err != nil {
+		app.internalServerError(w, r, err)
+	}
+}
+
+// runAnalyticsMaintenance creates upcoming analytics partitions once a day
+// until ctx is cancelled, so events never land in the default partition.
+func (app *application) runAnalyticsMaintenance(ctx context.Context) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if err := app.store.Analytics.EnsurePartitions(ctx, time.Now().UTC(), analyticsPartitionMonths); err != nil {
+			app.logger.Errorw("creating analytics partitions", "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// stripQuery drops the query and fragment of a referrer, which can carry
+// tokens or other personal data from the referring site.
+func stripQuery(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	u.RawQuery, u.Fragment = "", ""
+
+	return u.String()
+}
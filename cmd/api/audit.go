@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// audit appends an entry to the audit log through s. Mutations pass the
+// Storage they run on inside WithTx, so that the entry is committed or
+// rolled back with the change it records. before and after are hashed
+// rather than stored; pass nil when the resource did not exist on that side
+// of the change.
+func (app *application) audit(r *http.Request, s *store.Storage, action, resourceType, resourceID string, before, after any) error {
+	event := &store.AuditEvent{
+		IP:           clientIP(r),
+		RequestID:    middleware.GetReqID(r.Context()),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		BeforeHash:   hashState(before),
+		AfterHash:    hashState(after),
+	}
+	if principal := getPrincipal(r); principal != "" {
+		event.Actor = &principal
+	}
+
+	return s.Audit.Create(r.Context(), event)
+}
+
+// clientIP returns the request's remote address without its port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// nilIfMissing turns a nil pointer into an untyped nil so that audit records
+// no before state for resources that did not exist.
+func nilIfMissing[T any](v *T) any {
+	if v == nil {
+		return nil
+	}
+	return v
+}
+
+func hashState(state any) *string {
+	if state == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(state)
+	if err != nil {
+		return nil
+	}
+
+	sum := sha256.Sum256(b)
+	hash := hex.EncodeToString(sum[:])
+
+	return &hash
+}
+
+func (app *application) listAuditEventsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := store.AuditFilter{
+		Actor:        query.Get("actor"),
+		ResourceType: query.Get("resource_type"),
+		ResourceID:   query.Get("resource_id"),
+	}
+
+	for name, dst := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := query.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				app.badRequestResponse(w, r, fmt.Errorf("query parameter %s must be an RFC 3339 timestamp", name))
+				return
+			}
+			*dst = t
+		}
+	}
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	ctx := r.Context()
+
+	result, err := app.store.Audit.List(ctx, filter, store.NewPaginationParams(limit, offset))
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, result); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// runAuditRetention periodically removes audit events older than the
+// configured retention until ctx is cancelled.
+func (app *application) runAuditRetention(ctx context.Context) {
+	ticker := time.NewTicker(app.config.Audit.RetentionInterval)
+	defer ticker.Stop()
+
+	for {
+		cutoff := time.Now().Add(-app.config.Audit.Retention)
+
+		removed, err := app.store.Audit.DeleteBefore(ctx, cutoff)
+		if err != nil {
+			app.logger.Errorw("expiring audit events", "error", err.Error())
+		} else if removed > 0 {
+			app.logger.Infow("expired audit events", "count", removed, "before", cutoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// recordingAudit stores audit events in memory, or fails with err.
+type recordingAudit struct {
+	events []*store.AuditEvent
+	err    error
+}
+
+func (a *recordingAudit) Create(ctx context.Context, event *store.AuditEvent) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.events = append(a.events, event)
+	return nil
+}
+
+func (a *recordingAudit) List(context.Context, store.AuditFilter, store.PaginationParams) (*store.PaginatedResponse[*store.AuditEvent], error) {
+	return nil, nil
+}
+
+func (a *recordingAudit) DeleteBefore(context.Context, time.Time) (int64, error) { return 0, nil }
+
+// createdExperiences assigns IDs on Create.
+type createdExperiences struct {
+	store.ExperiencesRepository
+}
+
+func (createdExperiences) Create(ctx context.Context, e *store.Experience) error {
+	e.ID = 42
+	return nil
+}
+
+func TestMutationFailsWithoutAuditEntry(t *testing.T) {
+	body := `{"title":"Engineer","company":"Acme","start_date":"2020-01","end_date":"Present","description":["Built things"]}`
+
+	tests := []struct {
+		name   string
+		audit  *recordingAudit
+		status int
+		events int
+	}{
+		{name: "audited", audit: &recordingAudit{}, status: http.StatusCreated, events: 1},
+		{name: "audit fails", audit: &recordingAudit{err: errors.New("connection reset")}, status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &application{
+				logger: zap.NewNop().Sugar(),
+				store:  &store.Storage{Experiences: createdExperiences{}, Audit: tt.audit},
+			}
+
+			w := httptest.NewRecorder()
+			app.createExperienceHandler(w, httptest.NewRequest(http.MethodPost, "/v1/experiences", strings.NewReader(body)))
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if len(tt.audit.events) != tt.events {
+				t.Fatalf("recorded %d audit events, want %d", len(tt.audit.events), tt.events)
+			}
+			if tt.events > 0 {
+				if e := tt.audit.events[0]; e.Action != "experience.create" || e.ResourceID != "42" || e.IP != "192.0.2.1" || e.AfterHash == nil || e.BeforeHash != nil {
+					t.Errorf("audit event = %+v", e)
+				}
+			}
+		})
+	}
+}
+
+func TestRevokeAuditsPriorState(t *testing.T) {
+	audit := &recordingAudit{}
+	links := &memoryShareLinks{links: map[int64]*store.ShareLink{
+		7: {ID: 7, ExpiresAt: time.Now().Add(time.Hour)},
+	}}
+	app := &application{
+		logger: zap.NewNop().Sugar(),
+		store:  &store.Storage{ShareLinks: links, Audit: audit},
+	}
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "7")
+	r := httptest.NewRequest(http.MethodDelete, "/v1/admin/share-links/7", nil)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	w := httptest.NewRecorder()
+	app.revokeShareLinkHandler(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body)
+	}
+	if len(audit.events) != 1 {
+		t.Fatalf("recorded %d audit events, want 1", len(audit.events))
+	}
+	if e := audit.events[0]; e.Action != "share_link.revoke" || e.BeforeHash == nil || e.AfterHash != nil {
+		t.Errorf("audit event = %+v", e)
+	}
+}
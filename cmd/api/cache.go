@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// publicCacheControl lets browsers and CDNs reuse public content briefly
+	// and then revalidate it with a conditional request.
+	publicCacheControl = "public, max-age=60, stale-while-revalidate=300"
+	// privateCacheControl keeps responses that depend on who is asking, such
+	// as authenticated or share-token views, out of shared caches.
+	privateCacheControl = "private, no-cache"
+	noStoreCacheControl = "no-store"
+)
+
+// cacheControlMiddleware sets the Cache-Control header for a route. Public
+// routes fall back to privateCacheControl for authenticated callers and
+// share-token requests, since those may include non-public content.
+func cacheControlMiddleware(value string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cacheControl := value
+			if value == publicCacheControl {
+				w.Header().Add("Vary", "Authorization")
+				if getPrincipal(r) != "" || r.URL.Query().Has(shareTokenParam) {
+					cacheControl = privateCacheControl
+				}
+			}
+
+			w.Header().Set("Cache-Control", cacheControl)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bufferedResponseWriter holds a response so that its validators can be
+// computed before anything is sent.
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponseWriter) Header() http.Header { return b.header }
+
+func (b *bufferedResponseWriter) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// conditionalGetMiddleware gives successful GET responses a strong ETag
+// derived from the body and answers If-None-Match / If-Modified-Since with
+// 304 Not Modified. Handlers may set Last-Modified themselves.
+func (app *application) conditionalGetMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buf := &bufferedResponseWriter{header: w.Header()}
+		next.ServeHTTP(buf, r)
+
+		// A handler that writes nothing has, like with net/http, sent 200.
+		if buf.status == 0 {
+			buf.status = http.StatusOK
+		}
+
+		if buf.status != http.StatusOK {
+			w.WriteHeader(buf.status)
+			w.Write(buf.body.Bytes())
+			return
+		}
+
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			sum := sha256.Sum256(buf.body.Bytes())
+			etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+			w.Header().Set("ETag", etag)
+		}
+
+		if notModified(r, etag, w.Header().Get("Last-Modified")) {
+			for _, h := range []string{"Content-Type", "Content-Length", "Content-Disposition"} {
+				w.Header().Del(h)
+			}
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if r.Method != http.MethodHead {
+			w.Write(buf.body.Bytes())
+		}
+	})
+}
+
+// notModified evaluates the request's preconditions as described in
+// RFC 9110 section 13.2.2: If-None-Match takes precedence over
+// If-Modified-Since.
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+
+	return !modified.After(since)
+}
+
+// setLastModified sets Last-Modified from a timestamp read from the store.
+func setLastModified(w http.ResponseWriter, timestamp string) {
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return
+	}
+	w.Header().Set("Last-Modified", t.UTC().Format(http.TimeFormat))
+}
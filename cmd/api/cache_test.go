@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConditionalGet(t *testing.T) {
+	app := &application{}
+	handler := app.conditionalGetMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[]}`))
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/experiences", nil))
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Body.String() != `{"data":[]}` {
+		t.Fatalf("got %d, ETag %q, body %q", w.Code, etag, w.Body.String())
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/experiences", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("revalidation got %d with %d bytes, want 304 and no body", w.Code, w.Body.Len())
+	}
+}
+
+func TestConditionalGetEmptyResponse(t *testing.T) {
+	app := &application{}
+	handler := app.conditionalGetMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/experiences", nil))
+
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == "" {
+		t.Errorf("got %d, ETag %q, want 200 with an ETag", w.Code, w.Header().Get("ETag"))
+	}
+}
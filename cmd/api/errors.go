@@ -1,57 +1,193 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// problemContentType is the media type of error responses (RFC 9457).
+const problemContentType = "application/problem+json"
+
+// Error codes identify a kind of failure independently of its message, so
+// that clients can branch on them. They never change once published.
+const (
+	codeBadRequest       = "bad_request"
+	codeValidationFailed = "validation_failed"
+	codeInvalidBody      = "invalid_body"
+	codeBodyTooLarge     = "body_too_large"
+	codeUnauthorized     = "unauthorized"
+	codeForbidden        = "forbidden"
+	codeNotFound         = "not_found"
+	codeConflict         = "conflict"
+	codeReferenced       = "referenced"
+	codeInvalid          = "invalid"
+	codeGone             = "gone"
+	codeRateLimited      = "rate_limited"
+	codeTimeout          = "timeout"
+	codeBatchFailed      = "batch_failed"
+	codeInternal         = "internal_error"
 )
 
+// appError is a failure to be reported to the client. Err is the cause,
+// which is logged but never shown. Results holds the outcome of each item
+// of a bulk request that failed as a whole.
+type appError struct {
+	Code    string
+	Status  int
+	Detail  string
+	Fields  []fieldError
+	Results any
+	Err     error
+}
+
+func (e *appError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.Detail
+}
+
+func (e *appError) Unwrap() error { return e.Err }
+
+// problem is an RFC 9457 problem details document. Code, RequestID, Fields
+// and Results are extension members.
+type problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Fields    []fieldError `json:"fields,omitempty"`
+	Results   any          `json:"results,omitempty"`
+}
+
+// writeProblem renders e as problem details.
+func writeProblem(w http.ResponseWriter, r *http.Request, e *appError) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(e.Status)
+
+	json.NewEncoder(w).Encode(&problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(e.Status),
+		Status:    e.Status,
+		Detail:    e.Detail,
+		Instance:  r.URL.Path,
+		Code:      e.Code,
+		RequestID: middleware.GetReqID(r.Context()),
+		Fields:    e.Fields,
+		Results:   e.Results,
+	})
+}
+
+// storeProblems describes the store's errors to clients.
+var storeProblems = []struct {
+	err error
+	*appError
+}{
+	{store.ErrNotFound, &appError{Code: codeNotFound, Status: http.StatusNotFound, Detail: "not found"}},
+	{store.ErrConflict, &appError{Code: codeConflict, Status: http.StatusConflict, Detail: "the resource already exists"}},
+	{store.ErrReferenced, &appError{Code: codeReferenced, Status: http.StatusConflict, Detail: "the resource is referenced by, or refers to, another that prevents this change"}},
+	{store.ErrInvalid, &appError{Code: codeInvalid, Status: http.StatusUnprocessableEntity, Detail: "the request contains a value that is not allowed"}},
+	{store.ErrTimeout, &appError{Code: codeTimeout, Status: http.StatusGatewayTimeout, Detail: "the request took too long to complete"}},
+	{context.DeadlineExceeded, &appError{Code: codeTimeout, Status: http.StatusGatewayTimeout, Detail: "the request took too long to complete"}},
+}
+
+// problemFor maps err onto the problem reported for it. Errors that are
+// neither an *appError nor known to the store are internal.
+func problemFor(err error) *appError {
+	var appErr *appError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	for _, p := range storeProblems {
+		if errors.Is(err, p.err) {
+			e := *p.appError
+			e.Err = err
+			return &e
+		}
+	}
+
+	return &appError{Code: codeInternal, Status: http.StatusInternalServerError, Detail: "the server encountered a problem", Err: err}
+}
+
+// errorResponse answers with the response matching err. Handlers pass
+// errors from the store here instead of deciding on a status themselves.
+func (app *application) errorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	e := problemFor(err)
+
+	if e.Status >= http.StatusInternalServerError {
+		app.requestLogger(r).Errorw("server error", "method", r.Method, "path", r.URL.Path, "code", e.Code, "error", err.Error())
+	} else {
+		app.requestLogger(r).Warnw("client error", "method", r.Method, "path", r.URL.Path, "code", e.Code, "error", err.Error())
+	}
+
+	writeProblem(w, r, e)
+}
+
 func (app *application) internalServerError(w http.ResponseWriter, r *http.Request, err error) {
-	app.logger.Errorw("internal error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
+	app.requestLogger(r).Errorw("internal error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
 
-	writeJSONError(w, http.StatusInternalServerError, "the server encountered a problem")
+	writeProblem(w, r, &appError{Code: codeInternal, Status: http.StatusInternalServerError, Detail: "the server encountered a problem"})
 }
 
 func (app *application) forbiddenResponse(w http.ResponseWriter, r *http.Request) {
-	app.logger.Warnw("forbidden", "method", r.Method, "path", r.URL.Path, "error")
+	app.requestLogger(r).Warnw("forbidden", "method", r.Method, "path", r.URL.Path)
 
-	writeJSONError(w, http.StatusForbidden, "forbidden")
+	writeProblem(w, r, &appError{Code: codeForbidden, Status: http.StatusForbidden, Detail: "forbidden"})
 }
 
+// badRequestResponse reports err to the client. Validation and decode
+// errors are described field by field; any other error's message is shown
+// as is.
 func (app *application) badRequestResponse(w http.ResponseWriter, r *http.Request, err error) {
-	app.logger.Warnf("bad request", "method", r.Method, "path", r.URL.Path, "error", err.Error())
-
-	writeJSONError(w, http.StatusBadRequest, err.Error())
-}
+	app.requestLogger(r).Warnw("bad request", "method", r.Method, "path", r.URL.Path, "error", err.Error())
 
-func (app *application) conflictResponse(w http.ResponseWriter, r *http.Request, err error) {
-	app.logger.Errorf("conflict response", "method", r.Method, "path", r.URL.Path, "error", err.Error())
+	if e := app.invalidRequestError(r, err); e != nil {
+		writeProblem(w, r, e)
+		return
+	}
 
-	writeJSONError(w, http.StatusConflict, err.Error())
+	writeProblem(w, r, &appError{Code: codeBadRequest, Status: http.StatusBadRequest, Detail: err.Error()})
 }
 
 func (app *application) notFoundResponse(w http.ResponseWriter, r *http.Request, err error) {
-	app.logger.Warnf("not found error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
+	app.requestLogger(r).Warnw("not found error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
 
-	writeJSONError(w, http.StatusNotFound, "not found")
+	writeProblem(w, r, &appError{Code: codeNotFound, Status: http.StatusNotFound, Detail: "not found"})
 }
 
 func (app *application) unauthorizedErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
-	app.logger.Warnf("unauthorized error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
+	app.requestLogger(r).Warnw("unauthorized error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
 
-	writeJSONError(w, http.StatusUnauthorized, "unauthorized")
+	writeProblem(w, r, &appError{Code: codeUnauthorized, Status: http.StatusUnauthorized, Detail: "unauthorized"})
 }
 
 func (app *application) unauthorizedBasicErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
-	app.logger.Warnf("unauthorized basic error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
+	app.requestLogger(r).Warnw("unauthorized basic error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
 
 	w.Header().Set("WWW-Authenticate", `Basic realm="restricted", charset="UTF-8"`)
 
-	writeJSONError(w, http.StatusUnauthorized, "unauthorized")
+	writeProblem(w, r, &appError{Code: codeUnauthorized, Status: http.StatusUnauthorized, Detail: "unauthorized"})
 }
 
 func (app *application) rateLimitExceededResponse(w http.ResponseWriter, r *http.Request, retryAfter string) {
-	app.logger.Warnw("rate limit exceeded", "method", r.Method, "path", r.URL.Path)
+	app.requestLogger(r).Warnw("rate limit exceeded", "method", r.Method, "path", r.URL.Path)
 
 	w.Header().Set("Retry-After", retryAfter)
 
-	writeJSONError(w, http.StatusTooManyRequests, "rate limit exceeded, retry after: "+retryAfter)
+	writeProblem(w, r, &appError{Code: codeRateLimited, Status: http.StatusTooManyRequests, Detail: "rate limit exceeded, retry after: " + retryAfter})
+}
+
+func (app *application) goneResponse(w http.ResponseWriter, r *http.Request, err error) {
+	app.requestLogger(r).Warnw("gone", "method", r.Method, "path", r.URL.Path, "error", err.Error())
+
+	writeProblem(w, r, &appError{Code: codeGone, Status: http.StatusGone, Detail: err.Error()})
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+func TestProblemFor(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{store.ErrNotFound, http.StatusNotFound, codeNotFound},
+		{fmt.Errorf("loading: %w", store.ErrConflict), http.StatusConflict, codeConflict},
+		{store.ErrReferenced, http.StatusConflict, codeReferenced},
+		{store.ErrInvalid, http.StatusUnprocessableEntity, codeInvalid},
+		{store.ErrTimeout, http.StatusGatewayTimeout, codeTimeout},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout, codeTimeout},
+		{&appError{Code: codeGone, Status: http.StatusGone}, http.StatusGone, codeGone},
+		{errors.New("connection refused"), http.StatusInternalServerError, codeInternal},
+	}
+
+	for _, tt := range tests {
+		e := problemFor(tt.err)
+		if e.Status != tt.status || e.Code != tt.code {
+			t.Errorf("problemFor(%v) = %d %s, want %d %s", tt.err, e.Status, e.Code, tt.status, tt.code)
+		}
+		if !errors.Is(e, tt.err) {
+			t.Errorf("problemFor(%v) does not wrap its cause", tt.err)
+		}
+	}
+}
+
+func TestErrorResponseWritesProblem(t *testing.T) {
+	app := &application{logger: zap.NewNop().Sugar()}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/v1/experiences/batch", nil)
+	app.errorResponse(w, r, &appError{
+		Code:    codeBatchFailed,
+		Status:  http.StatusUnprocessableEntity,
+		Detail:  "rolled back",
+		Results: []batchResultResponse{{Index: 0, Op: store.BatchDelete, Status: store.BatchFailed, Code: codeNotFound}},
+	})
+
+	if w.Code != http.StatusUnprocessableEntity || w.Header().Get("Content-Type") != problemContentType {
+		t.Fatalf("got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	var body struct {
+		Code    string                `json:"code"`
+		Results []batchResultResponse `json:"results"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Code != codeBatchFailed || len(body.Results) != 1 || body.Results[0].Code != codeNotFound {
+		t.Errorf("body = %+v", body)
+	}
+}
+
+func TestPathID(t *testing.T) {
+	tests := []struct {
+		value    string
+		id       int64
+		notFound bool
+	}{
+		{"42", 42, false},
+		{"abc", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", tt.value)
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		id, err := pathID(r, "id")
+		if id != tt.id || errors.Is(err, store.ErrNotFound) != tt.notFound {
+			t.Errorf("pathID(%q) = %d, %v", tt.value, id, err)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"github.com/vatanak10/portfolio-backend/internal/events"
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// subscribe registers the side effects of content changes with bus. Each
+// event is handled by a single replica, so this is only for effects that
+// must happen once; caches held by every replica are invalidated from the
+// change notifications instead (see stream.Listen).
+func (app *application) subscribe(bus *events.Bus) {
+	bus.Subscribe("webhooks", app.store.Webhooks.Enqueue, store.WebhookEvents...)
+}
@@ -14,6 +14,12 @@ type experiencePayload struct {
 	Company     string   `json:"company" validate:"required"`
 	StartDate   string   `json:"start_date" validate:"required"`
 	EndDate     string   `json:"end_date" validate:"required"`
+	Pinned      *bool    `json:"pinned"`
+	Visibility  string   `json:"visibility" validate:"omitempty,oneof=public unlisted private"`
+}
+
+type experienceOrderPayload struct {
+	IDs []int64 `json:"ids" validate:"required,min=1,unique,dive,gt=0"`
 }
 
 func (app *application) createExperienceHandler(w http.ResponseWriter, r *http.Request) {
@@ -35,12 +41,20 @@ func (app *application) createExperienceHandler(w http.ResponseWriter, r *http.R
 		Company:     payload.Company,
 		StartDate:   payload.StartDate,
 		EndDate:     payload.EndDate,
+		Pinned:      payload.Pinned != nil && *payload.Pinned,
+		Visibility:  payload.Visibility,
 	}
 
 	ctx := r.Context()
 
-	if err := app.store.Experiences.Create(ctx, experience); err != nil {
-		app.internalServerError(w, r, err)
+	err := app.store.WithTx(ctx, func(tx *store.Storage) error {
+		if err := tx.Experiences.Create(ctx, experience); err != nil {
+			return err
+		}
+		return app.audit(r, tx, "experience.create", store.ExperiencesResource, strconv.FormatInt(experience.ID, 10), nil, experience)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
 		return
 	}
 
@@ -73,15 +87,15 @@ func (app *application) listExperiencesHandler(w http.ResponseWriter, r *http.Re
 		}
 
 		params := store.NewPaginationParams(limit, offset)
-		result, err = app.store.Experiences.List(ctx, params)
+		result, err = app.store.Experiences.List(ctx, listFilter(r), params)
 
 		if err != nil {
-			app.internalServerError(w, r, err)
+			app.errorResponse(w, r, err)
 			return
 		}
 	} else {
 		// No pagination parameters - get all results
-		result, err = app.store.Experiences.List(ctx)
+		result, err = app.store.Experiences.List(ctx, listFilter(r))
 	}
 
 	if err != nil {
@@ -89,6 +103,13 @@ func (app *application) listExperiencesHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	locales, err := app.translateExperiences(r, app.negotiateLocale(r), result.Data)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+	setContentLanguage(w, locales)
+
 	// Return the result
 	if err := writeJSON(w, http.StatusOK, result); err != nil {
 		app.internalServerError(w, r, err)
@@ -103,10 +124,28 @@ func (app *application) getExperienceHandler(w http.ResponseWriter, r *http.Requ
 
 	experience, err := app.store.Experiences.Get(ctx, id)
 	if err != nil {
-		app.notFoundResponse(w, r, err)
+		app.errorResponse(w, r, err)
 		return
 	}
 
+	if !app.canView(r, experience) {
+		app.notFoundResponse(w, r, store.ErrNotFound)
+		return
+	}
+
+	locales, err := app.translateExperiences(r, app.negotiateLocale(r), []*store.Experience{experience})
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+	setContentLanguage(w, locales)
+
+	// Translations carry their own timestamps, so only untranslated
+	// content can be validated by modification time.
+	if len(locales) == 1 && locales[0] == app.locales.Default() {
+		setLastModified(w, experience.UpdatedAt)
+	}
+
 	if err := app.jsonResponse(w, http.StatusOK, experience); err != nil {
 		app.internalServerError(w, r, err)
 		return
@@ -132,26 +171,38 @@ func (app *application) updateExperienceHandler(w http.ResponseWriter, r *http.R
 
 	experience, err := app.store.Experiences.Get(ctx, id)
 	if err != nil {
-		app.notFoundResponse(w, r, err)
+		app.errorResponse(w, r, err)
 		return
 	}
 
+	before := *experience
+
 	experience.Title = payload.Title
 	experience.Description = payload.Description
 	experience.Company = payload.Company
 	experience.StartDate = payload.StartDate
 	experience.EndDate = payload.EndDate
+	if payload.Pinned != nil {
+		experience.Pinned = *payload.Pinned
+	}
+	if payload.Visibility != "" {
+		experience.Visibility = payload.Visibility
+	}
 
-	// Convert id from string to int64
-	idInt, err := strconv.ParseInt(id, 10, 64)
+	experience.ID, err = pathID(r, "id")
 	if err != nil {
-		app.badRequestResponse(w, r, err)
+		app.errorResponse(w, r, err)
 		return
 	}
-	experience.ID = idInt
 
-	if err := app.store.Experiences.Update(ctx, experience); err != nil {
-		app.internalServerError(w, r, err)
+	err = app.store.WithTx(ctx, func(tx *store.Storage) error {
+		if err := tx.Experiences.Update(ctx, experience); err != nil {
+			return err
+		}
+		return app.audit(r, tx, "experience.update", store.ExperiencesResource, id, &before, experience)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
 		return
 	}
 
@@ -166,8 +217,18 @@ func (app *application) deleteExperienceHandler(w http.ResponseWriter, r *http.R
 
 	ctx := r.Context()
 
-	if err := app.store.Experiences.Delete(ctx, id); err != nil {
-		app.notFoundResponse(w, r, err)
+	err := app.store.WithTx(ctx, func(tx *store.Storage) error {
+		before, err := tx.Experiences.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Experiences.Delete(ctx, id); err != nil {
+			return err
+		}
+		return app.audit(r, tx, "experience.delete", store.ExperiencesResource, id, before, nil)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
 		return
 	}
 
@@ -176,3 +237,43 @@ func (app *application) deleteExperienceHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 }
+
+// reorderExperiencesHandler sets the display order from an ordered list of
+// IDs and responds with the experiences in their new order.
+func (app *application) reorderExperiencesHandler(w http.ResponseWriter, r *http.Request) {
+	var payload experienceOrderPayload
+
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	err := app.store.WithTx(ctx, func(tx *store.Storage) error {
+		if err := tx.Experiences.Reorder(ctx, payload.IDs); err != nil {
+			return err
+		}
+		return app.audit(r, tx, "experience.reorder", store.ExperiencesResource, "", nil, payload.IDs)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	result, err := app.store.Experiences.List(ctx, listFilter(r))
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, result.Data); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+type batchOperationPayload struct {
+	Op   string             `json:"op" validate:"required,oneof=create update delete"`
+	ID   int64              `json:"id" validate:"required_unless=Op create,excluded_if=Op create"`
+	Data *experiencePayload `json:"data" validate:"required_unless=Op delete,excluded_if=Op delete"`
+}
+
+type batchExperiencesPayload struct {
+	Operations []batchOperationPayload `json:"operations" validate:"required,min=1,max=100,dive"`
+}
+
+type batchResultResponse struct {
+	Index      int               `json:"index"`
+	Op         string            `json:"op"`
+	Status     string            `json:"status"`
+	Experience *store.Experience `json:"experience,omitempty"`
+	// Code and Error describe a failed operation as errorResponse would.
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+var batchAuditActions = map[string]string{
+	store.BatchCreate: "experience.create",
+	store.BatchUpdate: "experience.update",
+	store.BatchDelete: "experience.delete",
+}
+
+// batchExperiencesHandler applies a list of create, update and delete
+// operations in one transaction. By default the batch is all or nothing;
+// with ?atomic=false failed operations are reported and the rest committed.
+func (app *application) batchExperiencesHandler(w http.ResponseWriter, r *http.Request) {
+	atomic := true
+	if value := r.URL.Query().Get("atomic"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			app.badRequestResponse(w, r, fmt.Errorf("query parameter atomic must be a boolean"))
+			return
+		}
+		atomic = parsed
+	}
+
+	var payload batchExperiencesPayload
+
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	ops := make([]store.BatchOperation, len(payload.Operations))
+	for i, item := range payload.Operations {
+		ops[i] = store.BatchOperation{Op: item.Op, ID: item.ID}
+		if item.Data != nil {
+			ops[i].Experience = &store.Experience{
+				Title:       item.Data.Title,
+				Description: item.Data.Description,
+				Company:     item.Data.Company,
+				StartDate:   item.Data.StartDate,
+				EndDate:     item.Data.EndDate,
+				Pinned:      item.Data.Pinned != nil && *item.Data.Pinned,
+				Visibility:  item.Data.Visibility,
+			}
+			ops[i].Pinned = item.Data.Pinned
+		}
+	}
+
+	ctx := r.Context()
+
+	var results []store.BatchResult
+	err := app.store.WithTx(ctx, func(tx *store.Storage) error {
+		var err error
+		results, err = tx.Experiences.Batch(ctx, ops, atomic)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			if result.Status != store.BatchApplied {
+				continue
+			}
+
+			after := any(result.After)
+			if result.Op == store.BatchDelete {
+				after = nil
+			}
+			if err := app.audit(r, tx, batchAuditActions[result.Op], store.ExperiencesResource,
+				strconv.FormatInt(result.After.ID, 10), nilIfMissing(result.Before), after); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, store.ErrBatchFailed) {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	response := make([]batchResultResponse, len(results))
+	for i, result := range results {
+		response[i] = batchResultResponse{
+			Index:      result.Index,
+			Op:         result.Op,
+			Status:     result.Status,
+			Experience: result.After,
+		}
+
+		if result.Err != nil {
+			e := problemFor(result.Err)
+			if e.Status >= http.StatusInternalServerError {
+				app.requestLogger(r).Errorw("batch operation failed", "index", result.Index, "op", result.Op, "error", result.Err.Error())
+			}
+			response[i].Code = e.Code
+			response[i].Error = e.Detail
+		}
+	}
+
+	if err != nil {
+		app.errorResponse(w, r, &appError{
+			Code:    codeBatchFailed,
+			Status:  http.StatusUnprocessableEntity,
+			Detail:  "an operation failed and the batch was rolled back",
+			Results: response,
+			Err:     err,
+		})
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, response); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,522 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/vatanak10/portfolio-backend/internal/dataloader"
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// maxConnectionSize is the largest page a connection returns, matching the
+// REST pagination limit.
+const maxConnectionSize = 100
+
+const graphqlCtx contextKey = "graphql"
+
+type graphqlPayload struct {
+	Query         string         `json:"query" validate:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// graphqlRequest is the per-request state shared by resolvers.
+type graphqlRequest struct {
+	r            *http.Request
+	translations *dataloader.Loader[translationKey, *store.Translation]
+	skills       *dataloader.Loader[int64, []*store.Skill]
+	projects     *dataloader.Loader[int64, []*store.Project]
+}
+
+type translationKey struct {
+	experienceID int64
+	locale       string
+}
+
+type experienceEdge struct {
+	Cursor string
+	Node   *store.Experience
+}
+
+type experienceConnection struct {
+	Edges      []experienceEdge
+	TotalCount int
+	PageInfo   pageInfo
+}
+
+type pageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     *string
+	EndCursor       *string
+}
+
+// graphqlHandler executes a GraphQL query sent as JSON in a POST body, or
+// in the query string of a GET request. Queries over the configured depth
+// or complexity are rejected before they run.
+func (app *application) graphqlHandler(w http.ResponseWriter, r *http.Request) {
+	var payload graphqlPayload
+
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		payload.Query = q.Get("query")
+		payload.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &payload.Variables); err != nil {
+				app.badRequestResponse(w, r, errors.New("variables must be a JSON object"))
+				return
+			}
+		}
+	} else if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(payload.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		app.graphqlErrorResponse(w, gqlerrors.FormatErrors(err))
+		return
+	}
+
+	cfg := app.config.GraphQL
+	cost, err := analyzeQuery(doc, payload.OperationName, payload.Variables, cfg.Introspection)
+	if err == nil {
+		err = cost.checkLimits(cfg.MaxDepth, cfg.MaxComplexity)
+	}
+	if err != nil {
+		app.graphqlErrorResponse(w, gqlerrors.FormatErrors(err))
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), graphqlCtx, &graphqlRequest{
+		r:            r,
+		translations: dataloader.New(app.loadTranslations),
+		skills:       dataloader.New(app.store.Skills.ListByExperiences),
+		projects:     dataloader.New(app.store.Projects.ListBySkills),
+	})
+
+	result := graphql.Do(graphql.Params{
+		Schema:         *app.graphql,
+		RequestString:  payload.Query,
+		OperationName:  payload.OperationName,
+		VariableValues: payload.Variables,
+		Context:        ctx,
+	})
+
+	if err := writeJSON(w, http.StatusOK, result); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// graphqlErrorResponse rejects a query in the GraphQL response format.
+func (app *application) graphqlErrorResponse(w http.ResponseWriter, errs []gqlerrors.FormattedError) {
+	writeJSON(w, http.StatusBadRequest, &graphql.Result{Errors: errs})
+}
+
+// loadTranslations fetches the translations for a batch of experiences,
+// with one query per requested locale.
+func (app *application) loadTranslations(ctx context.Context, keys []translationKey) (map[translationKey]*store.Translation, error) {
+	byLocale := map[string][]int64{}
+	for _, k := range keys {
+		byLocale[k.locale] = append(byLocale[k.locale], k.experienceID)
+	}
+
+	results := make(map[translationKey]*store.Translation, len(keys))
+	for locale, ids := range byLocale {
+		translations, err := app.store.Translations.ListByResources(ctx, store.ExperiencesResource, ids, locale)
+		if err != nil {
+			return nil, err
+		}
+		for id, t := range translations {
+			results[translationKey{experienceID: id, locale: locale}] = t
+		}
+	}
+
+	return results, nil
+}
+
+// newGraphQLSchema builds the schema: experiences, the skills they used
+// and the projects that used each skill.
+func (app *application) newGraphQLSchema() (graphql.Schema, error) {
+	projectType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Project",
+		Fields: graphql.Fields{
+			"id":          projectField(graphql.NewNonNull(graphql.ID), func(p *store.Project) any { return p.ID }),
+			"name":        projectField(graphql.NewNonNull(graphql.String), func(p *store.Project) any { return p.Name }),
+			"description": projectField(graphql.String, func(p *store.Project) any { return p.Description }),
+			"url":         projectField(graphql.String, func(p *store.Project) any { return p.URL }),
+			"createdAt":   projectField(graphql.NewNonNull(graphql.String), func(p *store.Project) any { return p.CreatedAt }),
+			"updatedAt":   projectField(graphql.NewNonNull(graphql.String), func(p *store.Project) any { return p.UpdatedAt }),
+		},
+	})
+
+	skillType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Skill",
+		Fields: graphql.Fields{
+			"id":        skillField(graphql.NewNonNull(graphql.ID), func(s *store.Skill) any { return s.ID }),
+			"name":      skillField(graphql.NewNonNull(graphql.String), func(s *store.Skill) any { return s.Name }),
+			"category":  skillField(graphql.String, func(s *store.Skill) any { return s.Category }),
+			"createdAt": skillField(graphql.NewNonNull(graphql.String), func(s *store.Skill) any { return s.CreatedAt }),
+			"updatedAt": skillField(graphql.NewNonNull(graphql.String), func(s *store.Skill) any { return s.UpdatedAt }),
+			"projects": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(projectType))),
+				Description: "Projects that used the skill, newest first.",
+				Resolve:     app.resolveSkillProjects,
+			},
+		},
+	})
+
+	translationType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ExperienceTranslation",
+		Description: "Localized fields of an experience. Fields left untranslated are null.",
+		Fields: graphql.Fields{
+			"locale":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"title":       &graphql.Field{Type: graphql.String},
+			"description": &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"company":     &graphql.Field{Type: graphql.String},
+			"updatedAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	experienceType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Experience",
+		Fields: graphql.Fields{
+			"id":          experienceField(graphql.ID, func(e *store.Experience) any { return e.ID }),
+			"title":       experienceField(graphql.String, func(e *store.Experience) any { return e.Title }),
+			"description": experienceField(graphql.NewList(graphql.NewNonNull(graphql.String)), func(e *store.Experience) any { return e.Description }),
+			"company":     experienceField(graphql.String, func(e *store.Experience) any { return e.Company }),
+			"startDate":   experienceField(graphql.String, func(e *store.Experience) any { return e.StartDate }),
+			"endDate":     experienceField(graphql.String, func(e *store.Experience) any { return e.EndDate }),
+			"position":    experienceField(graphql.Int, func(e *store.Experience) any { return e.Position }),
+			"pinned":      experienceField(graphql.Boolean, func(e *store.Experience) any { return e.Pinned }),
+			"visibility":  experienceField(graphql.String, func(e *store.Experience) any { return e.Visibility }),
+			"createdAt":   experienceField(graphql.String, func(e *store.Experience) any { return e.CreatedAt }),
+			"updatedAt":   experienceField(graphql.String, func(e *store.Experience) any { return e.UpdatedAt }),
+			"skills": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(skillType))),
+				Description: "Skills used in the experience, by name.",
+				Resolve:     app.resolveExperienceSkills,
+			},
+			"translation": &graphql.Field{
+				Type:        translationType,
+				Description: "The experience's translation into locale, or null if there is none.",
+				Args: graphql.FieldConfigArgument{
+					"locale": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: app.resolveTranslation,
+			},
+		},
+	})
+
+	edgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ExperienceEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(experienceEdge).Cursor, nil
+			}},
+			"node": &graphql.Field{Type: graphql.NewNonNull(experienceType), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(experienceEdge).Node, nil
+			}},
+		},
+	})
+
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(pageInfo).HasNextPage, nil
+			}},
+			"hasPreviousPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(pageInfo).HasPreviousPage, nil
+			}},
+			"startCursor": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(pageInfo).StartCursor, nil
+			}},
+			"endCursor": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(pageInfo).EndCursor, nil
+			}},
+		},
+	})
+
+	connectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ExperienceConnection",
+		Fields: graphql.Fields{
+			"edges": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType))), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(*experienceConnection).Edges, nil
+			}},
+			"nodes": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(experienceType))), Resolve: func(p graphql.ResolveParams) (any, error) {
+				edges := p.Source.(*experienceConnection).Edges
+				nodes := make([]*store.Experience, len(edges))
+				for i, edge := range edges {
+					nodes[i] = edge.Node
+				}
+				return nodes, nil
+			}},
+			"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfoType), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(*experienceConnection).PageInfo, nil
+			}},
+			"totalCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(*experienceConnection).TotalCount, nil
+			}},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"experiences": &graphql.Field{
+				Type:        graphql.NewNonNull(connectionType),
+				Description: "Experiences in display order, paginated with an opaque cursor.",
+				Args: graphql.FieldConfigArgument{
+					"first": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultConnectionSize},
+					"after": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: app.resolveExperiences,
+			},
+			"experience": &graphql.Field{
+				Type: experienceType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: app.resolveExperience,
+			},
+			"skills": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(skillType))),
+				Description: "Every skill, by name.",
+				Resolve:     app.resolveSkills,
+			},
+			"projects": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(projectType))),
+				Description: "Every project, newest first.",
+				Resolve:     app.resolveProjects,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+func experienceField(t graphql.Output, get func(*store.Experience) any) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewNonNull(t),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return get(p.Source.(*store.Experience)), nil
+		},
+	}
+}
+
+func skillField(t graphql.Output, get func(*store.Skill) any) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return get(p.Source.(*store.Skill)), nil
+		},
+	}
+}
+
+func projectField(t graphql.Output, get func(*store.Project) any) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return get(p.Source.(*store.Project)), nil
+		},
+	}
+}
+
+func (app *application) resolveExperiences(p graphql.ResolveParams) (any, error) {
+	req := p.Context.Value(graphqlCtx).(*graphqlRequest)
+
+	first, _ := p.Args["first"].(int)
+	if first < 1 || first > maxConnectionSize {
+		return nil, fmt.Errorf("first must be between 1 and %d", maxConnectionSize)
+	}
+
+	offset := 0
+	if after, ok := p.Args["after"].(string); ok {
+		n, err := decodeCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		offset = n + 1
+	}
+
+	page, err := app.store.Experiences.List(p.Context, listFilter(req.r), store.PaginationParams{Limit: first, Offset: offset})
+	if err != nil {
+		return nil, app.graphqlInternalError(req.r, err)
+	}
+
+	connection := &experienceConnection{
+		Edges:      make([]experienceEdge, len(page.Data)),
+		TotalCount: page.Pagination.Total,
+		PageInfo: pageInfo{
+			HasNextPage:     page.Pagination.HasNext,
+			HasPreviousPage: page.Pagination.HasPrev,
+		},
+	}
+	for i, experience := range page.Data {
+		connection.Edges[i] = experienceEdge{Cursor: encodeCursor(offset + i), Node: experience}
+	}
+	if n := len(connection.Edges); n > 0 {
+		connection.PageInfo.StartCursor = &connection.Edges[0].Cursor
+		connection.PageInfo.EndCursor = &connection.Edges[n-1].Cursor
+	}
+
+	return connection, nil
+}
+
+// resolveExperience returns null for experiences the caller may not see, as
+// the REST API answers 404 for them.
+func (app *application) resolveExperience(p graphql.ResolveParams) (any, error) {
+	req := p.Context.Value(graphqlCtx).(*graphqlRequest)
+
+	experience, err := app.store.Experiences.Get(p.Context, p.Args["id"].(string))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, app.graphqlInternalError(req.r, err)
+	}
+
+	if !app.canView(req.r, experience) {
+		return nil, nil
+	}
+
+	return experience, nil
+}
+
+// resolveTranslation defers to the request's loader, so the translations
+// of every experience in a result are fetched together.
+func (app *application) resolveTranslation(p graphql.ResolveParams) (any, error) {
+	req := p.Context.Value(graphqlCtx).(*graphqlRequest)
+	experience := p.Source.(*store.Experience)
+	locale := p.Args["locale"].(string)
+
+	thunk := req.translations.Load(p.Context, translationKey{experienceID: experience.ID, locale: locale})
+
+	return func() (any, error) {
+		t, err := thunk()
+		if err != nil {
+			return nil, app.graphqlInternalError(req.r, err)
+		}
+		if t == nil {
+			return nil, nil
+		}
+
+		var fields experienceTranslationPayload
+		if err := json.Unmarshal(t.Fields, &fields); err != nil {
+			return nil, app.graphqlInternalError(req.r, err)
+		}
+
+		return map[string]any{
+			"locale":      t.Locale,
+			"title":       fields.Title,
+			"description": fields.Description,
+			"company":     fields.Company,
+			"updatedAt":   t.UpdatedAt,
+		}, nil
+	}, nil
+}
+
+// resolveExperienceSkills defers to the request's loader, so the skills of
+// every experience in a result are fetched with one query.
+func (app *application) resolveExperienceSkills(p graphql.ResolveParams) (any, error) {
+	req := p.Context.Value(graphqlCtx).(*graphqlRequest)
+	thunk := req.skills.Load(p.Context, p.Source.(*store.Experience).ID)
+
+	return func() (any, error) {
+		skills, err := thunk()
+		if err != nil {
+			return nil, app.graphqlInternalError(req.r, err)
+		}
+		if skills == nil {
+			return []*store.Skill{}, nil
+		}
+		return skills, nil
+	}, nil
+}
+
+// resolveSkillProjects batches like resolveExperienceSkills, one query for
+// the projects of every skill in a result.
+func (app *application) resolveSkillProjects(p graphql.ResolveParams) (any, error) {
+	req := p.Context.Value(graphqlCtx).(*graphqlRequest)
+	thunk := req.projects.Load(p.Context, p.Source.(*store.Skill).ID)
+
+	return func() (any, error) {
+		projects, err := thunk()
+		if err != nil {
+			return nil, app.graphqlInternalError(req.r, err)
+		}
+		if projects == nil {
+			return []*store.Project{}, nil
+		}
+		return projects, nil
+	}, nil
+}
+
+func (app *application) resolveSkills(p graphql.ResolveParams) (any, error) {
+	req := p.Context.Value(graphqlCtx).(*graphqlRequest)
+
+	skills, err := app.store.Skills.List(p.Context)
+	if err != nil {
+		return nil, app.graphqlInternalError(req.r, err)
+	}
+
+	return skills, nil
+}
+
+func (app *application) resolveProjects(p graphql.ResolveParams) (any, error) {
+	req := p.Context.Value(graphqlCtx).(*graphqlRequest)
+
+	projects, err := app.store.Projects.List(p.Context)
+	if err != nil {
+		return nil, app.graphqlInternalError(req.r, err)
+	}
+
+	return projects, nil
+}
+
+// graphqlInternalError logs err and returns an error safe to show clients.
+func (app *application) graphqlInternalError(r *http.Request, err error) error {
+	app.requestLogger(r).Errorw("graphql resolver error", "error", err.Error())
+	return errors.New("the server encountered a problem")
+}
+
+// Cursors are opaque to clients but are offsets into the same ordering the
+// REST list uses.
+const cursorPrefix = "offset:"
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		if v, ok := strings.CutPrefix(string(raw), cursorPrefix); ok {
+			if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+				return n, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid cursor %q", cursor)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// defaultConnectionSize is the page size assumed when costing a connection
+// whose size is not known up front, and the size assumed for lists.
+const defaultConnectionSize = 10
+
+// listFields are the fields returning a list or connection, whose children
+// are costed once per item.
+var listFields = map[string]bool{
+	"experiences": true,
+	"skills":      true,
+	"projects":    true,
+}
+
+var errIntrospectionDisabled = errors.New("introspection is disabled")
+
+// queryCost is the static cost of a GraphQL operation. Every field costs
+// one, multiplied by the page size of each connection it is nested in.
+type queryCost struct {
+	Depth      int
+	Complexity int
+}
+
+// queryAnalyzer computes the cost of an operation before it runs, expanding
+// fragments and resolving page sizes from literals or variables.
+type queryAnalyzer struct {
+	fragments     map[string]*ast.FragmentDefinition
+	variables     map[string]any
+	introspection bool
+}
+
+// analyzeQuery returns the cost of the operation named operationName in
+// doc, or of its only operation. It fails if the operation uses
+// introspection while it is disabled.
+func analyzeQuery(doc *ast.Document, operationName string, variables map[string]any, introspection bool) (queryCost, error) {
+	a := &queryAnalyzer{
+		fragments:     map[string]*ast.FragmentDefinition{},
+		variables:     variables,
+		introspection: introspection,
+	}
+
+	var operation *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			a.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				operation = def
+			}
+		}
+	}
+	if operation == nil {
+		// Let validation report the missing or ambiguous operation.
+		return queryCost{}, nil
+	}
+
+	return a.selectionSet(operation.SelectionSet, 1, map[string]bool{})
+}
+
+func (a *queryAnalyzer) selectionSet(set *ast.SelectionSet, multiplier int, visiting map[string]bool) (queryCost, error) {
+	var cost queryCost
+	if set == nil {
+		return cost, nil
+	}
+
+	for _, selection := range set.Selections {
+		var (
+			c   queryCost
+			err error
+		)
+
+		switch s := selection.(type) {
+		case *ast.Field:
+			c, err = a.field(s, multiplier, visiting)
+
+		case *ast.InlineFragment:
+			c, err = a.selectionSet(s.SelectionSet, multiplier, visiting)
+
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			fragment, ok := a.fragments[name]
+			if !ok || visiting[name] {
+				// Unknown fragments and cycles fail validation later.
+				continue
+			}
+
+			visiting[name] = true
+			c, err = a.selectionSet(fragment.SelectionSet, multiplier, visiting)
+			delete(visiting, name)
+		}
+
+		if err != nil {
+			return queryCost{}, err
+		}
+
+		cost.Depth = max(cost.Depth, c.Depth)
+		cost.Complexity += c.Complexity
+	}
+
+	return cost, nil
+}
+
+func (a *queryAnalyzer) field(f *ast.Field, multiplier int, visiting map[string]bool) (queryCost, error) {
+	name := f.Name.Value
+	if !a.introspection && strings.HasPrefix(name, "__") && name != "__typename" {
+		return queryCost{}, errIntrospectionDisabled
+	}
+
+	childMultiplier := multiplier
+	if size, ok := a.pageSize(f); ok {
+		childMultiplier *= size
+	}
+
+	children, err := a.selectionSet(f.SelectionSet, childMultiplier, visiting)
+	if err != nil {
+		return queryCost{}, err
+	}
+
+	return queryCost{
+		Depth:      children.Depth + 1,
+		Complexity: children.Complexity + multiplier,
+	}, nil
+}
+
+// pageSize returns the value of a field's "first" argument, if it has one,
+// or the assumed size of a list.
+func (a *queryAnalyzer) pageSize(f *ast.Field) (int, bool) {
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil && n > 0 {
+				return n, true
+			}
+		case *ast.Variable:
+			if n, ok := a.variables[v.Name.Value].(float64); ok && n > 0 {
+				return int(n), true
+			}
+		}
+		return defaultConnectionSize, true
+	}
+
+	if listFields[f.Name.Value] {
+		return defaultConnectionSize, true
+	}
+
+	return 0, false
+}
+
+// checkLimits reports an operation that exceeds the configured limits.
+func (c queryCost) checkLimits(maxDepth, maxComplexity int) error {
+	if c.Depth > maxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", c.Depth, maxDepth)
+	}
+	if c.Complexity > maxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", c.Complexity, maxComplexity)
+	}
+	return nil
+}
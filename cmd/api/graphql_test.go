@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"go.uber.org/zap"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// countingSkills serves skills and projects from maps, counting the batch
+// queries made.
+type countingSkills struct {
+	byExperience map[int64][]*store.Skill
+	bySkill      map[int64][]*store.Project
+	skillBatches [][]int64
+	projBatches  [][]int64
+}
+
+func (c *countingSkills) List(context.Context) ([]*store.Skill, error) { return nil, nil }
+
+func (c *countingSkills) ListByExperiences(ctx context.Context, ids []int64) (map[int64][]*store.Skill, error) {
+	c.skillBatches = append(c.skillBatches, ids)
+	return c.byExperience, nil
+}
+
+type countingProjects struct{ *countingSkills }
+
+func (c countingProjects) List(context.Context) ([]*store.Project, error) { return nil, nil }
+
+func (c countingProjects) ListBySkills(ctx context.Context, ids []int64) (map[int64][]*store.Project, error) {
+	c.projBatches = append(c.projBatches, ids)
+	return c.bySkill, nil
+}
+
+func TestGraphQLLoadsSkillsAndProjectsInBatches(t *testing.T) {
+	goSkill := &store.Skill{ID: 10, Name: "Go"}
+	sqlSkill := &store.Skill{ID: 11, Name: "SQL"}
+	skills := &countingSkills{
+		byExperience: map[int64][]*store.Skill{1: {goSkill, sqlSkill}, 2: {goSkill}},
+		bySkill:      map[int64][]*store.Project{10: {{ID: 100, Name: "portfolio-backend"}}},
+	}
+
+	app := &application{
+		logger: zap.NewNop().Sugar(),
+		config: config{GraphQL: graphqlConfig{MaxDepth: 8, MaxComplexity: 5000}},
+		store: &store.Storage{
+			Experiences: &listedExperiences{data: []*store.Experience{{ID: 1}, {ID: 2}, {ID: 3}}},
+			Skills:      skills,
+			Projects:    countingProjects{skills},
+		},
+	}
+	schema, err := app.newGraphQLSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.graphql = &schema
+
+	body := `{"query":"{ experiences { nodes { id skills { name projects { name } } } } }"}`
+	w := httptest.NewRecorder()
+	app.graphqlHandler(w, httptest.NewRequest(http.MethodPost, "/v1/graphql", strings.NewReader(body)))
+
+	var result struct {
+		Data struct {
+			Experiences struct {
+				Nodes []struct {
+					ID     string
+					Skills []struct {
+						Name     string
+						Projects []struct{ Name string }
+					}
+				}
+			}
+		}
+		Errors []any
+	}
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+
+	nodes := result.Data.Experiences.Nodes
+	if len(nodes) != 3 || len(nodes[0].Skills) != 2 || len(nodes[1].Skills) != 1 || nodes[2].Skills == nil || len(nodes[2].Skills) != 0 {
+		t.Fatalf("nodes = %+v", nodes)
+	}
+	if p := nodes[0].Skills[0].Projects; len(p) != 1 || p[0].Name != "portfolio-backend" {
+		t.Errorf("projects of Go = %+v", p)
+	}
+	if p := nodes[0].Skills[1].Projects; p == nil || len(p) != 0 {
+		t.Errorf("projects of SQL = %+v, want an empty list", p)
+	}
+
+	if len(skills.skillBatches) != 1 || !sameIDs(skills.skillBatches[0], 1, 2, 3) {
+		t.Errorf("skill queries = %v, want one for experiences 1, 2 and 3", skills.skillBatches)
+	}
+	if len(skills.projBatches) != 1 || !sameIDs(skills.projBatches[0], 10, 11) {
+		t.Errorf("project queries = %v, want one for skills 10 and 11", skills.projBatches)
+	}
+}
+
+func TestQueryCostCountsNestedLists(t *testing.T) {
+	doc, err := parser.Parse(parser.ParseParams{Source: `{ experiences(first: 5) { nodes { skills { projects { name } } } } }`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cost, err := analyzeQuery(doc, "", nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// experiences 1, nodes 5, skills 5, projects 5*10, name 5*10*10.
+	if want := 1 + 5 + 5 + 50 + 500; cost.Complexity != want {
+		t.Errorf("complexity = %d, want %d", cost.Complexity, want)
+	}
+}
+
+func sameIDs(got []int64, want ...int64) bool {
+	got = slices.Clone(got)
+	slices.Sort(got)
+	return slices.Equal(got, want)
+}
@@ -11,6 +11,8 @@ var Validate *validator.Validate
 
 func init() {
 	Validate = validator.New(validator.WithRequiredStructEnabled())
+	registerValidationMessages(Validate)
+	registerEnumRules(Validate)
 }
 
 func writeJSON(w http.ResponseWriter, status int, data any) error {
@@ -26,17 +28,10 @@ func readJSON(w http.ResponseWriter, r *http.Request, data any) error {
 	decoder := json.NewDecoder(r.Body)
 	decoder.DisallowUnknownFields()
 
-	return decoder.Decode(data)
-}
-
-func writeJSONError(w http.ResponseWriter, status int, message string) error {
-	type envelope struct {
-		Error string `json:"error"`
+	if err := decoder.Decode(data); err != nil {
+		return newDecodeError(err)
 	}
-
-	return writeJSON(w, status, &envelope{
-		Error: message,
-	})
+	return nil
 }
 
 func (app *application) jsonResponse(w http.ResponseWriter, status int, data any) error {
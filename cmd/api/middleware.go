@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+type contextKey string
+
+const (
+	loggerCtx    contextKey = "logger"
+	requestCtx   contextKey = "request"
+	principalCtx contextKey = "principal"
+)
+
+// requestInfo is shared by pointer so that values learned by inner middleware
+// (such as the authenticated principal) reach the access log entry.
+type requestInfo struct {
+	principal string
+}
+
+// requestLoggerMiddleware attaches a request-scoped logger to the context and
+// writes one access log entry per request once the handler has returned.
+func (app *application) requestLoggerMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		logger := app.logger.With(
+			"request_id", middleware.GetReqID(r.Context()),
+			"remote_ip", r.RemoteAddr,
+		)
+
+		info := &requestInfo{}
+
+		ctx := context.WithValue(r.Context(), loggerCtx, logger)
+		ctx = context.WithValue(ctx, requestCtx, info)
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		defer func() {
+			route := ""
+			if rctx := chi.RouteContext(ctx); rctx != nil {
+				route = rctx.RoutePattern()
+			}
+
+			fields := []any{
+				"method", r.Method,
+				"path", r.URL.Path,
+				"route", route,
+				"status", ww.Status(),
+				"bytes", ww.BytesWritten(),
+				"duration", time.Since(start),
+			}
+			if info.principal != "" {
+				fields = append(fields, "principal", info.principal)
+			}
+
+			switch status := ww.Status(); {
+			case status >= http.StatusInternalServerError:
+				logger.Errorw("request completed", fields...)
+			case status >= http.StatusBadRequest:
+				logger.Warnw("request completed", fields...)
+			default:
+				logger.Infow("request completed", fields...)
+			}
+		}()
+
+		next.ServeHTTP(ww, r.WithContext(ctx))
+	})
+}
+
+// requestLogger returns the logger attached by requestLoggerMiddleware, or the
+// application logger when the request did not pass through it.
+func (app *application) requestLogger(r *http.Request) *zap.SugaredLogger {
+	if logger, ok := r.Context().Value(loggerCtx).(*zap.SugaredLogger); ok {
+		return logger
+	}
+	return app.logger
+}
+
+// authenticateMiddleware identifies callers that present basic auth
+// credentials and attributes their store mutations to them. Requests
+// without credentials continue anonymously.
+func (app *application) authenticateMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cfg := app.config.Auth.Basic
+		if cfg.User == "" ||
+			subtle.ConstantTimeCompare([]byte(user), []byte(cfg.User)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(pass), []byte(cfg.Pass)) != 1 {
+			app.unauthorizedBasicErrorResponse(w, r, errors.New("invalid credentials"))
+			return
+		}
+
+		r = withPrincipal(r, user)
+		r = r.WithContext(store.WithActor(r.Context(), user))
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAuthMiddleware rejects requests that were not authenticated by
+// authenticateMiddleware.
+func (app *application) requireAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if getPrincipal(r) == "" {
+			app.unauthorizedBasicErrorResponse(w, r, errors.New("missing credentials"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withPrincipal records the authenticated principal on the request so that
+// it shows up in the access log and in every subsequent log line.
+func withPrincipal(r *http.Request, principal string) *http.Request {
+	ctx := context.WithValue(r.Context(), principalCtx, principal)
+
+	if info, ok := ctx.Value(requestCtx).(*requestInfo); ok {
+		info.principal = principal
+	}
+	if logger, ok := ctx.Value(loggerCtx).(*zap.SugaredLogger); ok {
+		ctx = context.WithValue(ctx, loggerCtx, logger.With("principal", principal))
+	}
+
+	return r.WithContext(ctx)
+}
+
+func getPrincipal(r *http.Request) string {
+	principal, _ := r.Context().Value(principalCtx).(string)
+	return principal
+}
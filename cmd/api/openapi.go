@@ -0,0 +1,357 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vatanak10/portfolio-backend/internal/openapi"
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+const (
+	apiTitle   = "Portfolio API"
+	apiVersion = "1.0.0"
+
+	openAPIPath = "/v1/openapi.json"
+
+	basicAuthScheme = "basicAuth"
+)
+
+// batchFailedProblem is the problem returned for an atomic batch that was
+// rolled back.
+type batchFailedProblem struct {
+	problem
+	Results []batchResultResponse `json:"results"`
+}
+
+// apiOperation documents one route registered in mount. Request and
+// response are zero values of the types the handler decodes and encodes;
+// their schemas are derived from the types by reflection.
+type apiOperation struct {
+	method  string
+	path    string
+	id      string
+	summary string
+	tag     string
+	auth    bool
+	params  []*openapi.Parameter
+
+	request any
+
+	status int
+	// response is nil for responses without a JSON body. enveloped
+	// responses are wrapped in {"data": ...} by jsonResponse.
+	response    any
+	enveloped   bool
+	contentType string
+	errors      []int
+	// errorBodies overrides the error schema for some statuses.
+	errorBodies map[int]any
+}
+
+func pathParam(name, description string, schema *openapi.Schema) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: "path", Description: description, Required: true, Schema: schema}
+}
+
+func queryParam(name, description string, schema *openapi.Schema) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func headerParam(name, description string) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: "header", Description: description, Schema: stringSchema("")}
+}
+
+func stringSchema(format string) *openapi.Schema {
+	return &openapi.Schema{Type: "string", Format: format}
+}
+
+func integerSchema() *openapi.Schema {
+	return &openapi.Schema{Type: "integer"}
+}
+
+func booleanSchema() *openapi.Schema {
+	return &openapi.Schema{Type: "boolean"}
+}
+
+var (
+	experienceIDParameter = pathParam("id", "Experience ID.", integerSchema())
+	webhookIDParameter    = pathParam("id", "Webhook ID.", integerSchema())
+	shareLinkIDParameter  = pathParam("id", "Share link ID.", integerSchema())
+	localeParameter       = pathParam("locale", "Translation locale.", stringSchema(""))
+	revisionParameter     = pathParam("rev", "Revision number.", integerSchema())
+	deliveryIDParameter   = pathParam("delivery", "Delivery ID.", integerSchema())
+
+	limitParameter  = queryParam("limit", "Page size.", integerSchema())
+	offsetParameter = queryParam("offset", "Number of items to skip.", integerSchema())
+	langParameter   = queryParam("lang", "Response locale, overriding Accept-Language.", stringSchema(""))
+	shareParameter  = queryParam(shareTokenParam, "Share token granting access to a private experience.", stringSchema(""))
+)
+
+var messageResponse = map[string]string{}
+
+// apiOperations lists every route in mount. openAPIDocument fails when a
+// route is missing from the list, or listed but not routed.
+var apiOperations = []apiOperation{
+	{method: http.MethodGet, path: "/v1/health", id: "getHealth", summary: "Report that the server is up", tag: "meta",
+		status: http.StatusOK, contentType: "text/plain"},
+	{method: http.MethodGet, path: openAPIPath, id: "getOpenAPI", summary: "This OpenAPI document", tag: "meta",
+		status: http.StatusOK, response: map[string]any{}},
+	{method: http.MethodGet, path: "/v1/docs", id: "getDocs", summary: "Interactive API documentation", tag: "meta",
+		status: http.StatusOK, contentType: "text/html"},
+
+	{method: http.MethodGet, path: "/v1/experiences", id: "listExperiences", summary: "List experiences", tag: "experiences",
+		params: []*openapi.Parameter{limitParameter, offsetParameter, langParameter},
+		status: http.StatusOK, response: store.PaginatedResponse[*store.Experience]{}},
+	{method: http.MethodPost, path: "/v1/experiences", id: "createExperience", summary: "Create an experience", tag: "experiences", auth: true,
+		request: experiencePayload{},
+		status:  http.StatusCreated, response: store.Experience{}, enveloped: true, errors: []int{http.StatusBadRequest}},
+	{method: http.MethodPost, path: "/v1/experiences/batch", id: "batchExperiences", summary: "Apply a batch of experience operations", tag: "experiences", auth: true,
+		params:  []*openapi.Parameter{queryParam("atomic", "Roll back the whole batch when an operation fails. Defaults to true.", booleanSchema())},
+		request: batchExperiencesPayload{},
+		status:  http.StatusOK, response: []batchResultResponse{}, enveloped: true, errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+		errorBodies: map[int]any{http.StatusUnprocessableEntity: batchFailedProblem{}}},
+	{method: http.MethodPut, path: "/v1/experiences/order", id: "reorderExperiences", summary: "Set the display order of experiences", tag: "experiences", auth: true,
+		request: experienceOrderPayload{},
+		status:  http.StatusOK, response: []*store.Experience{}, enveloped: true, errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodGet, path: "/v1/experiences/{id}", id: "getExperience", summary: "Get an experience", tag: "experiences",
+		params: []*openapi.Parameter{experienceIDParameter, langParameter, shareParameter},
+		status: http.StatusOK, response: store.Experience{}, enveloped: true, errors: []int{http.StatusNotFound}},
+	{method: http.MethodPut, path: "/v1/experiences/{id}", id: "updateExperience", summary: "Replace an experience", tag: "experiences", auth: true,
+		params:  []*openapi.Parameter{experienceIDParameter},
+		request: experiencePayload{},
+		status:  http.StatusOK, response: store.Experience{}, enveloped: true, errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodDelete, path: "/v1/experiences/{id}", id: "deleteExperience", summary: "Delete an experience", tag: "experiences", auth: true,
+		params: []*openapi.Parameter{experienceIDParameter},
+		status: http.StatusOK, response: messageResponse, enveloped: true, errors: []int{http.StatusNotFound}},
+	{method: http.MethodPost, path: "/v1/experiences/{id}/share", id: "shareExperience", summary: "Create a share token for an experience", tag: "experiences", auth: true,
+		params: []*openapi.Parameter{experienceIDParameter, queryParam("expires_in", "Token lifetime as a Go duration, such as 72h.", stringSchema(""))},
+		status: http.StatusCreated, response: experienceShareResponse{}, enveloped: true, errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+
+	{method: http.MethodGet, path: "/v1/experiences/{id}/translations", id: "listExperienceTranslations", summary: "List the translations of an experience", tag: "translations",
+		params: []*openapi.Parameter{experienceIDParameter, shareParameter},
+		status: http.StatusOK, response: []*store.Translation{}, enveloped: true, errors: []int{http.StatusNotFound}},
+	{method: http.MethodPut, path: "/v1/experiences/{id}/translations/{locale}", id: "upsertExperienceTranslation", summary: "Create or replace a translation", tag: "translations", auth: true,
+		params:  []*openapi.Parameter{experienceIDParameter, localeParameter},
+		request: experienceTranslationPayload{},
+		status:  http.StatusOK, response: store.Translation{}, enveloped: true, errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodDelete, path: "/v1/experiences/{id}/translations/{locale}", id: "deleteExperienceTranslation", summary: "Delete a translation", tag: "translations", auth: true,
+		params: []*openapi.Parameter{experienceIDParameter, localeParameter},
+		status: http.StatusOK, response: messageResponse, enveloped: true, errors: []int{http.StatusNotFound}},
+
+	{method: http.MethodGet, path: "/v1/experiences/{id}/revisions", id: "listExperienceRevisions", summary: "List the revisions of an experience", tag: "revisions",
+		params: []*openapi.Parameter{experienceIDParameter},
+		status: http.StatusOK, response: []*store.Revision{}, enveloped: true, errors: []int{http.StatusNotFound}},
+	{method: http.MethodGet, path: "/v1/experiences/{id}/revisions/diff", id: "diffExperienceRevisions", summary: "Compare two revisions", tag: "revisions",
+		params: []*openapi.Parameter{
+			experienceIDParameter,
+			{Name: "from", In: "query", Required: true, Schema: integerSchema()},
+			{Name: "to", In: "query", Required: true, Schema: integerSchema()},
+		},
+		status: http.StatusOK, response: revisionDiffResponse{}, enveloped: true, errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodGet, path: "/v1/experiences/{id}/revisions/{rev}", id: "getExperienceRevision", summary: "Get a revision", tag: "revisions",
+		params: []*openapi.Parameter{experienceIDParameter, revisionParameter},
+		status: http.StatusOK, response: store.Revision{}, enveloped: true, errors: []int{http.StatusNotFound}},
+	{method: http.MethodPost, path: "/v1/experiences/{id}/revisions/{rev}/revert", id: "revertExperienceRevision", summary: "Restore an experience to a revision", tag: "revisions", auth: true,
+		params: []*openapi.Parameter{experienceIDParameter, revisionParameter},
+		status: http.StatusOK, response: store.Experience{}, enveloped: true, errors: []int{http.StatusNotFound}},
+
+	{method: http.MethodGet, path: "/v1/export/resume.json", id: "exportResume", summary: "Export experiences as a JSON Resume document", tag: "resume",
+		status: http.StatusOK, response: resumeDocument{}},
+	{method: http.MethodPost, path: "/v1/import/resume", id: "importResume", summary: "Import the work section of a JSON Resume document", tag: "resume", auth: true,
+		params:  []*openapi.Parameter{queryParam("dry_run", "Report the changes without applying them.", booleanSchema())},
+		request: resumeDocument{},
+		status:  http.StatusOK, response: resumeImportResponse{}, enveloped: true, errors: []int{http.StatusBadRequest}},
+	{method: http.MethodGet, path: "/v1/resume.pdf", id: "getResumePDF", summary: "Render the resume as a PDF", tag: "resume",
+		params: []*openapi.Parameter{queryParam("template", "Layout to render.", stringSchema("")), langParameter},
+		status: http.StatusOK, contentType: "application/pdf", errors: []int{http.StatusBadRequest}},
+
+	{method: http.MethodGet, path: "/v1/shared/{token}", id: "getSharedContent", summary: "Get the content of a share link", tag: "sharing",
+		params: []*openapi.Parameter{pathParam("token", "Share link token.", stringSchema("")), headerParam(sharePasswordHeader, "Password of a protected link.")},
+		status: http.StatusOK, response: sharedContentResponse{}, enveloped: true,
+		errors: []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusGone, http.StatusTooManyRequests}},
+
+	{method: http.MethodPost, path: "/v1/analytics/events", id: "recordAnalyticsEvent", summary: "Record a page view or click", tag: "analytics",
+		request: analyticsEventPayload{},
+		status:  http.StatusAccepted, errors: []int{http.StatusBadRequest}},
+	{method: http.MethodGet, path: "/v1/analytics/summary", id: "getAnalyticsSummary", summary: "Summarize analytics by day and resource", tag: "analytics", auth: true,
+		params: []*openapi.Parameter{
+			queryParam("from", "First day, inclusive. Defaults to 30 days ago.", stringSchema("date")),
+			queryParam("to", "Last day, inclusive. Defaults to today.", stringSchema("date")),
+		},
+		status: http.StatusOK, response: store.AnalyticsSummary{}, enveloped: true, errors: []int{http.StatusBadRequest}},
+
+	{method: http.MethodGet, path: "/v1/events/stream", id: "streamEvents", summary: "Stream content changes as server-sent events", tag: "events", auth: true,
+		params: []*openapi.Parameter{
+			headerParam("Last-Event-ID", "Resume after this event."),
+			queryParam(lastEventIDParam, "Resume after this event, for clients that cannot set headers.", stringSchema("")),
+		},
+		status: http.StatusOK, contentType: "text/event-stream"},
+
+	{method: http.MethodGet, path: "/v1/graphql", id: "queryGraphQLGet", summary: "Run a GraphQL query from the query string", tag: "graphql",
+		params: []*openapi.Parameter{
+			{Name: "query", In: "query", Required: true, Schema: stringSchema("")},
+			queryParam("operationName", "", stringSchema("")),
+			queryParam("variables", "Variables as a JSON object.", stringSchema("")),
+		},
+		status: http.StatusOK, response: map[string]any{}, errors: []int{http.StatusBadRequest}},
+	{method: http.MethodPost, path: "/v1/graphql", id: "queryGraphQL", summary: "Run a GraphQL query", tag: "graphql",
+		request: graphqlPayload{},
+		status:  http.StatusOK, response: map[string]any{}, errors: []int{http.StatusBadRequest}},
+
+	{method: http.MethodGet, path: "/v1/admin/audit", id: "listAuditEvents", summary: "List audit log entries", tag: "admin", auth: true,
+		params: []*openapi.Parameter{
+			queryParam("actor", "", stringSchema("")),
+			queryParam("resource_type", "", stringSchema("")),
+			queryParam("resource_id", "", stringSchema("")),
+			queryParam("from", "", stringSchema("date-time")),
+			queryParam("to", "", stringSchema("date-time")),
+			limitParameter, offsetParameter,
+		},
+		status: http.StatusOK, response: store.PaginatedResponse[*store.AuditEvent]{}, errors: []int{http.StatusBadRequest}},
+
+	{method: http.MethodPost, path: "/v1/admin/share-links", id: "createShareLink", summary: "Create a share link", tag: "admin", auth: true,
+		request: shareLinkPayload{},
+		status:  http.StatusCreated, response: shareLinkResponse{}, enveloped: true, errors: []int{http.StatusBadRequest}},
+	{method: http.MethodGet, path: "/v1/admin/share-links", id: "listShareLinks", summary: "List share links", tag: "admin", auth: true,
+		status: http.StatusOK, response: []shareLinkResponse{}, enveloped: true},
+	{method: http.MethodDelete, path: "/v1/admin/share-links/{id}", id: "revokeShareLink", summary: "Revoke a share link", tag: "admin", auth: true,
+		params: []*openapi.Parameter{shareLinkIDParameter},
+		status: http.StatusOK, response: messageResponse, enveloped: true, errors: []int{http.StatusNotFound}},
+	{method: http.MethodGet, path: "/v1/admin/share-links/{id}/views", id: "listShareLinkViews", summary: "List the visits through a share link", tag: "admin", auth: true,
+		params: []*openapi.Parameter{shareLinkIDParameter},
+		status: http.StatusOK, response: []*store.ShareLinkView{}, enveloped: true, errors: []int{http.StatusNotFound}},
+
+	{method: http.MethodPost, path: "/v1/admin/webhooks", id: "createWebhook", summary: "Create a webhook", tag: "webhooks", auth: true,
+		request: webhookPayload{},
+		status:  http.StatusCreated, response: webhookResponse{}, enveloped: true, errors: []int{http.StatusBadRequest}},
+	{method: http.MethodGet, path: "/v1/admin/webhooks", id: "listWebhooks", summary: "List webhooks", tag: "webhooks", auth: true,
+		status: http.StatusOK, response: []*store.Webhook{}, enveloped: true},
+	{method: http.MethodGet, path: "/v1/admin/webhooks/{id}", id: "getWebhook", summary: "Get a webhook", tag: "webhooks", auth: true,
+		params: []*openapi.Parameter{webhookIDParameter},
+		status: http.StatusOK, response: store.Webhook{}, enveloped: true, errors: []int{http.StatusNotFound}},
+	{method: http.MethodPut, path: "/v1/admin/webhooks/{id}", id: "updateWebhook", summary: "Replace a webhook", tag: "webhooks", auth: true,
+		params:  []*openapi.Parameter{webhookIDParameter},
+		request: webhookPayload{},
+		status:  http.StatusOK, response: store.Webhook{}, enveloped: true, errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodDelete, path: "/v1/admin/webhooks/{id}", id: "deleteWebhook", summary: "Delete a webhook", tag: "webhooks", auth: true,
+		params: []*openapi.Parameter{webhookIDParameter},
+		status: http.StatusOK, response: messageResponse, enveloped: true, errors: []int{http.StatusNotFound}},
+	{method: http.MethodGet, path: "/v1/admin/webhooks/{id}/deliveries", id: "listWebhookDeliveries", summary: "List the deliveries of a webhook", tag: "webhooks", auth: true,
+		params: []*openapi.Parameter{webhookIDParameter, limitParameter, offsetParameter},
+		status: http.StatusOK, response: store.PaginatedResponse[*store.WebhookDelivery]{}, errors: []int{http.StatusNotFound}},
+	{method: http.MethodGet, path: "/v1/admin/webhooks/{id}/deliveries/{delivery}", id: "getWebhookDelivery", summary: "Get a delivery and its attempts", tag: "webhooks", auth: true,
+		params: []*openapi.Parameter{webhookIDParameter, deliveryIDParameter},
+		status: http.StatusOK, response: store.WebhookDelivery{}, enveloped: true, errors: []int{http.StatusNotFound}},
+	{method: http.MethodPost, path: "/v1/admin/webhooks/{id}/deliveries/{delivery}/redeliver", id: "redeliverWebhook", summary: "Queue a delivery again", tag: "webhooks", auth: true,
+		params: []*openapi.Parameter{webhookIDParameter, deliveryIDParameter},
+		status: http.StatusAccepted, response: store.WebhookDelivery{}, enveloped: true, errors: []int{http.StatusNotFound}},
+}
+
+// buildOpenAPIDocument describes the API from apiOperations. The tests
+// check that they match the routes registered by mount.
+func buildOpenAPIDocument() *openapi.Document {
+	gen := openapi.NewGenerator()
+	for rule, values := range enumRules {
+		gen.Enum(rule, values)
+	}
+	problemSchema := gen.Schema(problem{})
+
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:       apiTitle,
+			Version:     apiVersion,
+			Description: "Errors are RFC 9457 problem details (application/problem+json) with a stable code member. Most successful responses wrap their payload in {\"data\": ...}.",
+		},
+		Paths: map[string]*openapi.PathItem{},
+		Components: openapi.Components{
+			SecuritySchemes: map[string]*openapi.SecurityScheme{
+				basicAuthScheme: {
+					Type:        "http",
+					Scheme:      "basic",
+					Description: "Credentials from AUTH_BASIC_USER and AUTH_BASIC_PASS. Optional outside the operations that require it, where it attributes changes to the caller.",
+				},
+			},
+		},
+	}
+
+	var tags []string
+	for _, op := range apiOperations {
+		operation := &openapi.Operation{
+			OperationID: op.id,
+			Summary:     op.summary,
+			Tags:        []string{op.tag},
+			Parameters:  op.params,
+			Responses:   map[string]*openapi.Response{},
+		}
+		if !slices.Contains(tags, op.tag) {
+			tags = append(tags, op.tag)
+		}
+
+		if op.request != nil {
+			operation.RequestBody = &openapi.RequestBody{
+				Required: true,
+				Content:  map[string]*openapi.MediaType{"application/json": {Schema: gen.Schema(op.request)}},
+			}
+		}
+
+		response := &openapi.Response{Description: http.StatusText(op.status)}
+		switch {
+		case op.response != nil:
+			schema := gen.Schema(op.response)
+			if op.enveloped {
+				schema = &openapi.Schema{
+					Type:       "object",
+					Properties: map[string]*openapi.Schema{"data": schema},
+					Required:   []string{"data"},
+				}
+			}
+			response.Content = map[string]*openapi.MediaType{"application/json": {Schema: schema}}
+		case op.contentType != "":
+			response.Content = map[string]*openapi.MediaType{op.contentType: {}}
+		}
+		operation.Responses[strconv.Itoa(op.status)] = response
+
+		statuses := slices.Clone(op.errors)
+		if op.request != nil {
+			statuses = append(statuses, http.StatusRequestEntityTooLarge)
+		}
+		if op.auth {
+			operation.Security = []map[string][]string{{basicAuthScheme: {}}}
+			statuses = append(statuses, http.StatusUnauthorized)
+		}
+		for _, status := range append(statuses, http.StatusInternalServerError, http.StatusGatewayTimeout) {
+			content := map[string]*openapi.MediaType{problemContentType: {Schema: problemSchema}}
+			if body, ok := op.errorBodies[status]; ok {
+				content = map[string]*openapi.MediaType{problemContentType: {Schema: gen.Schema(body)}}
+			}
+			operation.Responses[strconv.Itoa(status)] = &openapi.Response{
+				Description: http.StatusText(status),
+				Content:     content,
+			}
+		}
+
+		item, ok := doc.Paths[op.path]
+		if !ok {
+			item = &openapi.PathItem{}
+			doc.Paths[op.path] = item
+		}
+		(*item)[strings.ToLower(op.method)] = operation
+	}
+
+	for _, tag := range tags {
+		doc.Tags = append(doc.Tags, openapi.Tag{Name: tag})
+	}
+	doc.Components.Schemas = gen.Schemas()
+
+	return doc
+}
+
+func (app *application) openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if err := writeJSON(w, http.StatusOK, app.openapi); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// routePath turns a chi route pattern into an OpenAPI path.
+func routePath(route string) string {
+	route = strings.ReplaceAll(route, "/*/", "/")
+	if len(route) > 1 {
+		route = strings.TrimSuffix(route, "/")
+	}
+	return route
+}
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	app := &application{}
+	doc := buildOpenAPIDocument()
+
+	documented := map[string]bool{}
+	for path, item := range doc.Paths {
+		for method := range *item {
+			documented[strings.ToUpper(method)+" "+path] = false
+		}
+	}
+
+	var undocumented []string
+	err := chi.Walk(app.mount(), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		key := method + " " + routePath(route)
+		if _, ok := documented[key]; !ok {
+			undocumented = append(undocumented, key)
+		}
+		documented[key] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var unrouted []string
+	for key, routed := range documented {
+		if !routed {
+			unrouted = append(unrouted, key)
+		}
+	}
+	slices.Sort(unrouted)
+
+	if len(undocumented) > 0 {
+		t.Errorf("routes missing from apiOperations: %v", undocumented)
+	}
+	if len(unrouted) > 0 {
+		t.Errorf("operations without a route: %v", unrouted)
+	}
+}
+
+func TestOpenAPIOperationIDsAreUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, op := range apiOperations {
+		if seen[op.id] {
+			t.Errorf("duplicate operation id %q", op.id)
+		}
+		seen[op.id] = true
+	}
+}
+
+var pathParamPattern = regexp.MustCompile(`\{[^}]+\}`)
+
+func TestOpenAPIAuthMatchesRoutes(t *testing.T) {
+	app := &application{logger: zap.NewNop().Sugar()}
+	mux := app.mount()
+
+	for _, op := range apiOperations {
+		if !op.auth {
+			continue
+		}
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(op.method, pathParamPattern.ReplaceAllString(op.path, "1"), nil))
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s is documented as requiring credentials but answered %d", op.method, op.path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// pathID parses the named URL parameter as the ID of a resource. A value
+// that is not a number names no resource, so the error wraps
+// store.ErrNotFound and errorResponse answers it with 404, just as when the
+// store is queried with it.
+func pathID(r *http.Request, name string) (int64, error) {
+	value := chi.URLParam(r, name)
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s %q is not a number", store.ErrNotFound, name, value)
+	}
+
+	return id, nil
+}
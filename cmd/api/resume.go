@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+const (
+	resumeSchemaURL = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+
+	// currentEndDate is stored for roles that have not ended; JSON Resume
+	// expresses the same thing by omitting endDate.
+	currentEndDate = "Present"
+)
+
+// resumeDocument is a JSON Resume (https://jsonresume.org/schema) document.
+// Sections without a counterpart in the store are accepted and ignored.
+type resumeDocument struct {
+	Schema       string          `json:"$schema,omitempty"`
+	Basics       json.RawMessage `json:"basics,omitempty"`
+	Work         []resumeWork    `json:"work" validate:"dive"`
+	Volunteer    json.RawMessage `json:"volunteer,omitempty"`
+	Education    json.RawMessage `json:"education,omitempty"`
+	Awards       json.RawMessage `json:"awards,omitempty"`
+	Certificates json.RawMessage `json:"certificates,omitempty"`
+	Publications json.RawMessage `json:"publications,omitempty"`
+	Skills       json.RawMessage `json:"skills,omitempty"`
+	Languages    json.RawMessage `json:"languages,omitempty"`
+	Interests    json.RawMessage `json:"interests,omitempty"`
+	References   json.RawMessage `json:"references,omitempty"`
+	Projects     json.RawMessage `json:"projects,omitempty"`
+	Meta         json.RawMessage `json:"meta,omitempty"`
+}
+
+type resumeImportResponse struct {
+	DryRun  bool                 `json:"dry_run"`
+	Results []store.UpsertResult `json:"results"`
+}
+
+type resumeWork struct {
+	Name        string   `json:"name" validate:"required"`
+	Position    string   `json:"position" validate:"required"`
+	Location    string   `json:"location,omitempty"`
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	StartDate   string   `json:"startDate" validate:"required"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Highlights  []string `json:"highlights"`
+}
+
+func resumeWorkFromExperience(e *store.Experience) resumeWork {
+	work := resumeWork{
+		Name:       e.Company,
+		Position:   e.Title,
+		StartDate:  e.StartDate,
+		EndDate:    e.EndDate,
+		Highlights: e.Description,
+	}
+	if strings.EqualFold(work.EndDate, currentEndDate) {
+		work.EndDate = ""
+	}
+	if work.Highlights == nil {
+		work.Highlights = []string{}
+	}
+
+	return work
+}
+
+func (w resumeWork) experience() *store.Experience {
+	experience := &store.Experience{
+		Title:       w.Position,
+		Description: w.Highlights,
+		Company:     w.Name,
+		StartDate:   w.StartDate,
+		EndDate:     w.EndDate,
+	}
+	if experience.EndDate == "" {
+		experience.EndDate = currentEndDate
+	}
+	if experience.Description == nil {
+		experience.Description = []string{}
+	}
+
+	return experience
+}
+
+func (app *application) exportResumeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	experiences, err := app.store.Experiences.List(ctx, listFilter(r))
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	doc := resumeDocument{
+		Schema: resumeSchemaURL,
+		Work:   make([]resumeWork, 0, len(experiences.Data)),
+	}
+	for _, experience := range experiences.Data {
+		doc.Work = append(doc.Work, resumeWorkFromExperience(experience))
+	}
+
+	if err := writeJSON(w, http.StatusOK, doc); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) importResumeHandler(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			app.badRequestResponse(w, r, errors.New("query parameter dry_run must be a boolean"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	var doc resumeDocument
+
+	if err := readJSON(w, r, &doc); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(doc); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	experiences := make([]*store.Experience, 0, len(doc.Work))
+	for _, work := range doc.Work {
+		experiences = append(experiences, work.experience())
+	}
+
+	ctx := r.Context()
+
+	var results []store.UpsertResult
+	err := app.store.WithTx(ctx, func(tx *store.Storage) error {
+		var err error
+		results, err = tx.Experiences.Upsert(ctx, experiences, dryRun)
+		if err != nil || dryRun {
+			return err
+		}
+		return app.audit(r, tx, "resume.import", store.ExperiencesResource, "", nil, results)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	response := resumeImportResponse{
+		DryRun:  dryRun,
+		Results: results,
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, response); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/vatanak10/portfolio-backend/internal/resume"
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// pdfCache keeps rendered résumés per template and audience until the
+// underlying content changes. Each invalidation starts a new generation, so
+// that a rendering begun before a change is not cached after it.
+type pdfCache struct {
+	mu         sync.RWMutex
+	files      map[string][]byte
+	generation uint64
+}
+
+func newPDFCache() *pdfCache {
+	return &pdfCache{files: map[string][]byte{}}
+}
+
+// get returns the file cached for key, along with the current generation
+// to pass to set when there is none.
+func (c *pdfCache) get(key string) ([]byte, uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	b, ok := c.files[key]
+	return b, c.generation, ok
+}
+
+// set caches b for key unless the cache was invalidated since generation.
+func (c *pdfCache) set(key string, b []byte, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation == c.generation {
+		c.files[key] = b
+	}
+}
+
+func (c *pdfCache) invalidate(string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.files = map[string][]byte{}
+	c.generation++
+}
+
+func (app *application) resumePDFHandler(w http.ResponseWriter, r *http.Request) {
+	template := r.URL.Query().Get("template")
+	if template == "" {
+		template = resume.DefaultTemplate
+	}
+
+	if !slices.Contains(resume.Templates(), template) {
+		app.badRequestResponse(w, r, fmt.Errorf("unknown template %q, expected one of: %s",
+			template, strings.Join(resume.Templates(), ", ")))
+		return
+	}
+
+	// Authenticated callers also see unlisted and private experiences, so
+	// their rendering is cached separately.
+	key := template
+	if getPrincipal(r) != "" {
+		key += ":all"
+	}
+
+	b, generation, ok := app.resumePDFs.get(key)
+	if !ok {
+		ctx := r.Context()
+
+		experiences, err := app.store.Experiences.List(ctx, listFilter(r))
+		if err != nil {
+			app.errorResponse(w, r, err)
+			return
+		}
+
+		skills, err := app.resumeSkills(r, experiences.Data)
+		if err != nil {
+			app.errorResponse(w, r, err)
+			return
+		}
+
+		var buf bytes.Buffer
+		doc := resume.Document{
+			Name:        app.config.Resume.Name,
+			Experiences: experiences.Data,
+			Skills:      skills,
+		}
+		if err := resume.RenderPDF(&buf, doc, template, app.resumeFont); err != nil {
+			app.internalServerError(w, r, err)
+			return
+		}
+
+		b = buf.Bytes()
+		app.resumePDFs.set(key, b, generation)
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="resume.pdf"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// resumeSkills returns the skills used in experiences, each once, by name.
+func (app *application) resumeSkills(r *http.Request, experiences []*store.Experience) ([]*store.Skill, error) {
+	ids := make([]int64, len(experiences))
+	for i, experience := range experiences {
+		ids[i] = experience.ID
+	}
+
+	byExperience, err := app.store.Skills.ListByExperiences(r.Context(), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[int64]bool{}
+	var skills []*store.Skill
+	for _, list := range byExperience {
+		for _, skill := range list {
+			if !seen[skill.ID] {
+				seen[skill.ID] = true
+				skills = append(skills, skill)
+			}
+		}
+	}
+	slices.SortFunc(skills, func(a, b *store.Skill) int { return strings.Compare(a.Name, b.Name) })
+
+	return skills, nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestPDFCacheDropsRenderingsStartedBeforeInvalidation(t *testing.T) {
+	c := newPDFCache()
+
+	_, generation, ok := c.get("classic")
+	if ok {
+		t.Fatal("empty cache returned a file")
+	}
+
+	// A write commits while the résumé is being rendered.
+	c.invalidate("experiences")
+	c.set("classic", []byte("stale"), generation)
+
+	if _, _, ok := c.get("classic"); ok {
+		t.Error("rendering started before the invalidation was cached")
+	}
+
+	_, generation, _ = c.get("classic")
+	c.set("classic", []byte("fresh"), generation)
+
+	if b, _, ok := c.get("classic"); !ok || string(b) != "fresh" {
+		t.Errorf("get = %q, %v; want fresh, true", b, ok)
+	}
+}
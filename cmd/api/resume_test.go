@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// upsertRecorder records the dryRun flag of each Upsert.
+type upsertRecorder struct {
+	store.ExperiencesRepository
+	dryRuns []bool
+}
+
+func (u *upsertRecorder) Upsert(ctx context.Context, experiences []*store.Experience, dryRun bool) ([]store.UpsertResult, error) {
+	u.dryRuns = append(u.dryRuns, dryRun)
+	return []store.UpsertResult{}, nil
+}
+
+func TestImportResumeDryRun(t *testing.T) {
+	body := `{"work":[{"name":"Acme","position":"Engineer","startDate":"2020-01","highlights":["Built things"]}]}`
+
+	tests := []struct {
+		query  string
+		status int
+		dryRun []bool
+	}{
+		{query: "", status: http.StatusOK, dryRun: []bool{false}},
+		{query: "?dry_run=true", status: http.StatusOK, dryRun: []bool{true}},
+		{query: "?dry_run=1", status: http.StatusOK, dryRun: []bool{true}},
+		{query: "?dry_run=false", status: http.StatusOK, dryRun: []bool{false}},
+		{query: "?dry_run=yes", status: http.StatusBadRequest},
+		{query: "?dry_run=1%20", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			experiences := &upsertRecorder{}
+			app := &application{
+				logger: zap.NewNop().Sugar(),
+				store:  &store.Storage{Experiences: experiences, Audit: &recordingAudit{}},
+			}
+
+			w := httptest.NewRecorder()
+			app.importResumeHandler(w, httptest.NewRequest(http.MethodPost, "/v1/import/resume"+tt.query, strings.NewReader(body)))
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if len(experiences.dryRuns) != len(tt.dryRun) || (len(tt.dryRun) > 0 && experiences.dryRuns[0] != tt.dryRun[0]) {
+				t.Errorf("Upsert calls with dryRun %v, want %v", experiences.dryRuns, tt.dryRun)
+			}
+		})
+	}
+}
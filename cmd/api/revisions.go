@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+type revisionDiffResponse struct {
+	From    int                          `json:"from"`
+	To      int                          `json:"to"`
+	Changes map[string]store.FieldChange `json:"changes"`
+}
+
+func (app *application) listExperienceRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if !app.ensureExperienceVisible(w, r, chi.URLParam(r, "id")) {
+		return
+	}
+
+	ctx := r.Context()
+
+	revisions, err := app.store.Revisions.List(ctx, id)
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if len(revisions) == 0 {
+		app.notFoundResponse(w, r, store.ErrNotFound)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, revisions); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) getExperienceRevisionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	rev, err := pathID(r, "rev")
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if !app.ensureExperienceVisible(w, r, chi.URLParam(r, "id")) {
+		return
+	}
+
+	ctx := r.Context()
+
+	revision, err := app.store.Revisions.Get(ctx, id, int(rev))
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, revision); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) diffExperienceRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	fromRev, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		app.badRequestResponse(w, r, errors.New("query parameter from must be a revision number"))
+		return
+	}
+
+	toRev, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil {
+		app.badRequestResponse(w, r, errors.New("query parameter to must be a revision number"))
+		return
+	}
+
+	if !app.ensureExperienceVisible(w, r, chi.URLParam(r, "id")) {
+		return
+	}
+
+	ctx := r.Context()
+
+	from, err := app.store.Revisions.Get(ctx, id, fromRev)
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	to, err := app.store.Revisions.Get(ctx, id, toRev)
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	diff := revisionDiffResponse{
+		From:    fromRev,
+		To:      toRev,
+		Changes: store.DiffRevisions(from, to),
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, diff); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) revertExperienceRevisionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	rev, err := pathID(r, "rev")
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	var experience *store.Experience
+	err = app.store.WithTx(ctx, func(tx *store.Storage) error {
+		// The experience may be soft-deleted, in which case there is no
+		// current state to hash.
+		before, err := tx.Experiences.Get(ctx, strconv.FormatInt(id, 10))
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return err
+		}
+
+		experience, err = tx.Experiences.Revert(ctx, id, int(rev))
+		if err != nil {
+			return err
+		}
+
+		return app.audit(r, tx, "experience.revert", store.ExperiencesResource, strconv.FormatInt(id, 10), nilIfMissing(before), experience)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, experience); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/vatanak10/portfolio-backend/internal/seed"
+)
+
+func (app *application) seed(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	file := fs.String("file", "", "YAML or JSON fixture file (defaults to the bundled sample data)")
+	reset := fs.Bool("reset", false, "permanently delete existing content first (not allowed in production)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *reset && app.config.Env == "production" {
+		return errors.New("seed --reset is disabled in production")
+	}
+
+	var (
+		fixtures *seed.Fixtures
+		err      error
+	)
+	if *file != "" {
+		fixtures, err = seed.Load(*file)
+	} else {
+		fixtures, err = seed.Sample()
+	}
+	if err != nil {
+		return err
+	}
+
+	result, err := seed.Run(ctx, app.store, fixtures, *reset)
+	if err != nil {
+		return err
+	}
+
+	app.logger.Infow("seed completed", "experiences", result.String())
+
+	return nil
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vatanak10/portfolio-backend/internal/share"
+	"github.com/vatanak10/portfolio-backend/internal/store"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// sharePasswordHeader carries the password of a protected share link.
+const sharePasswordHeader = "X-Share-Password"
+
+type shareLinkPayload struct {
+	Scope       string  `json:"scope" validate:"required,oneof=resume experiences"`
+	ResourceIDs []int64 `json:"resource_ids" validate:"required_if=Scope experiences,excluded_if=Scope resume,unique,dive,gt=0"`
+	Label       string  `json:"label" validate:"max=255"`
+	ExpiresIn   string  `json:"expires_in"`
+	MaxUses     *int    `json:"max_uses" validate:"omitempty,min=1"`
+	Password    string  `json:"password" validate:"omitempty,min=8,max=72"`
+}
+
+type shareLinkResponse struct {
+	*store.ShareLink
+	HasPassword bool   `json:"has_password"`
+	Token       string `json:"token,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+type sharedContentResponse struct {
+	Scope       string              `json:"scope"`
+	Label       *string             `json:"label"`
+	ExpiresAt   time.Time           `json:"expires_at"`
+	Experiences []*store.Experience `json:"experiences"`
+}
+
+func newShareLinkResponse(link *store.ShareLink) shareLinkResponse {
+	return shareLinkResponse{ShareLink: link, HasPassword: link.PasswordHash != nil}
+}
+
+func (app *application) createShareLinkHandler(w http.ResponseWriter, r *http.Request) {
+	var payload shareLinkPayload
+
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	ttl := app.config.Share.TTL
+	if payload.ExpiresIn != "" {
+		d, err := time.ParseDuration(payload.ExpiresIn)
+		if err != nil || d <= 0 {
+			app.badRequestResponse(w, r, fmt.Errorf("expires_in must be a positive duration such as 72h"))
+			return
+		}
+		ttl = d
+	}
+
+	ctx := r.Context()
+
+	if payload.Scope == store.ShareScopeExperiences {
+		found, err := app.store.Experiences.List(ctx, store.ExperienceFilter{IDs: payload.ResourceIDs})
+		if err != nil {
+			app.errorResponse(w, r, err)
+			return
+		}
+		if len(found.Data) != len(payload.ResourceIDs) {
+			app.badRequestResponse(w, r, errors.New("resource_ids must name existing experiences"))
+			return
+		}
+	}
+
+	link := &store.ShareLink{
+		Scope:       payload.Scope,
+		ResourceIDs: payload.ResourceIDs,
+		MaxUses:     payload.MaxUses,
+		ExpiresAt:   time.Now().Add(ttl).Truncate(time.Second).UTC(),
+	}
+	if payload.Label != "" {
+		link.Label = &payload.Label
+	}
+	if payload.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(payload.Password), bcrypt.DefaultCost)
+		if err != nil {
+			app.internalServerError(w, r, err)
+			return
+		}
+		encoded := string(hash)
+		link.PasswordHash = &encoded
+	}
+
+	err := app.store.WithTx(ctx, func(tx *store.Storage) error {
+		if err := tx.ShareLinks.Create(ctx, link); err != nil {
+			return err
+		}
+		return app.audit(r, tx, "share_link.create", store.ShareLinksResource, strconv.FormatInt(link.ID, 10), nil, link)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	token := app.shares.Sign(share.Claims{
+		Subject:   shareSubject(store.ShareLinksResource, link.ID),
+		ExpiresAt: link.ExpiresAt,
+	})
+
+	response := newShareLinkResponse(link)
+	response.Token = token
+	response.URL = "/v1/shared/" + token
+
+	if err := app.jsonResponse(w, http.StatusCreated, response); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) listShareLinksHandler(w http.ResponseWriter, r *http.Request) {
+	links, err := app.store.ShareLinks.List(r.Context())
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	response := make([]shareLinkResponse, len(links))
+	for i, link := range links {
+		response[i] = newShareLinkResponse(link)
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, response); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) revokeShareLinkHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := app.shareLinkIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	err := app.store.WithTx(r.Context(), func(tx *store.Storage) error {
+		before, err := tx.ShareLinks.Get(r.Context(), id)
+		if err != nil {
+			return err
+		}
+		if err := tx.ShareLinks.Revoke(r.Context(), id); err != nil {
+			return err
+		}
+		return app.audit(r, tx, "share_link.revoke", store.ShareLinksResource, chi.URLParam(r, "id"), before, nil)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, map[string]string{"message": "revoked successfully"}); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) listShareLinkViewsHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := app.shareLinkIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+
+	if _, err := app.store.ShareLinks.Get(ctx, id); err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	views, err := app.store.ShareLinks.ListViews(ctx, id)
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, views); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// sharedContentHandler serves the content a share link is scoped to,
+// regardless of visibility, and records the visit. Links that are expired,
+// revoked or used up answer 410 Gone; unknown or forged tokens 404.
+func (app *application) sharedContentHandler(w http.ResponseWriter, r *http.Request) {
+	claims, err := app.shares.Verify(chi.URLParam(r, "token"))
+	switch {
+	case errors.Is(err, share.ErrExpiredToken):
+		app.goneResponse(w, r, store.ErrShareLinkUnavailable)
+		return
+	case err != nil:
+		app.notFoundResponse(w, r, err)
+		return
+	}
+
+	rawID, ok := strings.CutPrefix(claims.Subject, store.ShareLinksResource+"/")
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if !ok || err != nil {
+		app.notFoundResponse(w, r, share.ErrInvalidToken)
+		return
+	}
+
+	ctx := r.Context()
+
+	link, err := app.store.ShareLinks.Get(ctx, id)
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if link.PasswordHash != nil {
+		password := r.Header.Get(sharePasswordHeader)
+		if bcrypt.CompareHashAndPassword([]byte(*link.PasswordHash), []byte(password)) != nil {
+			app.unauthorizedErrorResponse(w, r, errors.New("missing or wrong share link password"))
+			return
+		}
+	}
+
+	if err := app.store.ShareLinks.RecordView(ctx, link.ID, r.RemoteAddr, r.UserAgent()); err != nil {
+		switch {
+		case errors.Is(err, store.ErrShareLinkUnavailable):
+			app.goneResponse(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	filter := store.ExperienceFilter{}
+	if link.Scope == store.ShareScopeExperiences {
+		filter.IDs = link.ResourceIDs
+	}
+
+	experiences, err := app.store.Experiences.List(ctx, filter)
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	locales, err := app.translateExperiences(r, app.negotiateLocale(r), experiences.Data)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+	setContentLanguage(w, locales)
+
+	response := sharedContentResponse{
+		Scope:       link.Scope,
+		Label:       link.Label,
+		ExpiresAt:   link.ExpiresAt,
+		Experiences: experiences.Data,
+	}
+	if response.Experiences == nil {
+		response.Experiences = []*store.Experience{}
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, response); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) shareLinkIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return 0, false
+	}
+	return id, true
+}
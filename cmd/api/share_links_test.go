@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/vatanak10/portfolio-backend/internal/i18n"
+	"github.com/vatanak10/portfolio-backend/internal/share"
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// memoryShareLinks keeps share links in memory, enforcing availability in
+// RecordView the way the Postgres store does.
+type memoryShareLinks struct {
+	mu    sync.Mutex
+	links map[int64]*store.ShareLink
+	views int
+}
+
+func (m *memoryShareLinks) Create(ctx context.Context, link *store.ShareLink) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link.ID = int64(len(m.links) + 1)
+	m.links[link.ID] = link
+	return nil
+}
+
+func (m *memoryShareLinks) List(ctx context.Context) ([]*store.ShareLink, error) { return nil, nil }
+
+func (m *memoryShareLinks) Get(ctx context.Context, id int64) (*store.ShareLink, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link, ok := m.links[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	copied := *link
+	return &copied, nil
+}
+
+func (m *memoryShareLinks) Revoke(ctx context.Context, id int64) error { return nil }
+
+func (m *memoryShareLinks) RecordView(ctx context.Context, id int64, ip, userAgent string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link, ok := m.links[id]
+	if !ok || link.RevokedAt != nil || !time.Now().Before(link.ExpiresAt) ||
+		(link.MaxUses != nil && link.Uses >= *link.MaxUses) {
+		return store.ErrShareLinkUnavailable
+	}
+	link.Uses++
+	m.views++
+	return nil
+}
+
+func (m *memoryShareLinks) ListViews(ctx context.Context, id int64) ([]*store.ShareLinkView, error) {
+	return nil, nil
+}
+
+// listedExperiences returns a fixed page from List.
+type listedExperiences struct {
+	store.ExperiencesRepository
+	data []*store.Experience
+}
+
+func (l *listedExperiences) List(context.Context, store.ExperienceFilter, ...store.PaginationParams) (*store.PaginatedResponse[*store.Experience], error) {
+	return &store.PaginatedResponse[*store.Experience]{Data: l.data}, nil
+}
+
+func newShareTestApp(t *testing.T) (*application, *memoryShareLinks, http.Handler) {
+	t.Helper()
+
+	locales, err := i18n.NewNegotiator("en", []string{"en"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	links := &memoryShareLinks{links: map[int64]*store.ShareLink{}}
+	app := &application{
+		logger:  zap.NewNop().Sugar(),
+		locales: locales,
+		shares:  share.NewSigner([]byte("secret")),
+		store: &store.Storage{
+			ShareLinks:  links,
+			Experiences: &listedExperiences{data: []*store.Experience{{ID: 1, Title: "Engineer"}}},
+		},
+	}
+
+	mux := chi.NewRouter()
+	mux.Get("/v1/shared/{token}", app.sharedContentHandler)
+
+	return app, links, mux
+}
+
+// issue stores link and returns a token for it.
+func issue(t *testing.T, app *application, link *store.ShareLink) string {
+	t.Helper()
+	if err := app.store.ShareLinks.Create(context.Background(), link); err != nil {
+		t.Fatal(err)
+	}
+	return app.shares.Sign(share.Claims{Subject: shareSubject(store.ShareLinksResource, link.ID), ExpiresAt: link.ExpiresAt})
+}
+
+func getShared(mux http.Handler, token string) int {
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/shared/"+token, nil))
+	return w.Code
+}
+
+func TestSharedContentUseCountExhaustion(t *testing.T) {
+	app, links, mux := newShareTestApp(t)
+
+	maxUses := 2
+	token := issue(t, app, &store.ShareLink{Scope: store.ShareScopeResume, MaxUses: &maxUses, ExpiresAt: time.Now().Add(time.Hour)})
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusGone, http.StatusGone} {
+		if got := getShared(mux, token); got != want {
+			t.Errorf("request %d: status %d, want %d", i+1, got, want)
+		}
+	}
+	if links.views != maxUses {
+		t.Errorf("recorded %d views, want %d", links.views, maxUses)
+	}
+}
+
+func TestSharedContentTokens(t *testing.T) {
+	app, _, mux := newShareTestApp(t)
+
+	valid := issue(t, app, &store.ShareLink{Scope: store.ShareScopeResume, ExpiresAt: time.Now().Add(time.Hour)})
+	revokedAt := "2026-01-01T00:00:00Z"
+	revoked := issue(t, app, &store.ShareLink{Scope: store.ShareScopeResume, RevokedAt: &revokedAt, ExpiresAt: time.Now().Add(time.Hour)})
+	expired := issue(t, app, &store.ShareLink{Scope: store.ShareScopeResume, ExpiresAt: time.Now().Add(-time.Minute)})
+	forged := share.NewSigner([]byte("other")).Sign(share.Claims{Subject: shareSubject(store.ShareLinksResource, 1), ExpiresAt: time.Now().Add(time.Hour)})
+	unknown := app.shares.Sign(share.Claims{Subject: shareSubject(store.ShareLinksResource, 99), ExpiresAt: time.Now().Add(time.Hour)})
+	otherSubject := app.shares.Sign(share.Claims{Subject: "experiences/1", ExpiresAt: time.Now().Add(time.Hour)})
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"valid", valid, http.StatusOK},
+		{"revoked", revoked, http.StatusGone},
+		{"expired", expired, http.StatusGone},
+		{"tampered", valid[:len(valid)-4] + "AAAA", http.StatusNotFound},
+		{"forged", forged, http.StatusNotFound},
+		{"unknown link", unknown, http.StatusNotFound},
+		{"other subject", otherSubject, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		if got := getShared(mux, tt.token); got != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
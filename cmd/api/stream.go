@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vatanak10/portfolio-backend/internal/stream"
+)
+
+// lastEventIDParam lets clients that cannot set the Last-Event-ID header on
+// their first connection resume from a known event.
+const lastEventIDParam = "last_event_id"
+
+// streamEventsHandler pushes content change notifications as Server-Sent
+// Events. Each event's ID is that of its outbox entry; a client reconnecting
+// with Last-Event-ID first receives the changes this replica received after
+// that event, or a reset event when it cannot tell what was missed. Comment
+// lines are sent as heartbeats so that idle connections are not closed by
+// proxies.
+func (app *application) streamEventsHandler(w http.ResponseWriter, r *http.Request) {
+	lastID := int64(0)
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		lastID, _ = strconv.ParseInt(v, 10, 64)
+	} else if v := r.URL.Query().Get(lastEventIDParam); v != "" {
+		lastID, _ = strconv.ParseInt(v, 10, 64)
+	}
+
+	// The connection outlives the server's write timeout.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	sub, replay, complete := app.streams.Subscribe(lastID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if !complete {
+		replay = []stream.Message{{Event: stream.ResetEvent}}
+	}
+	for _, m := range replay {
+		if err := writeEvent(w, m); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(app.config.Stream.Heartbeat)
+	defer heartbeat.Stop()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case m, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, m); err != nil {
+				return
+			}
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes m in the text/event-stream format. Reset events carry
+// no ID so that they do not move the client's Last-Event-ID.
+func writeEvent(w http.ResponseWriter, m stream.Message) error {
+	if m.ID != 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", m.ID); err != nil {
+			return err
+		}
+	}
+
+	data := m.Data
+	if data == nil {
+		data = []byte("{}")
+	}
+
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", m.Event, data)
+	return err
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// translationsResource identifies translations in the audit log, where
+// their ID is "<experience id>/<locale>".
+const translationsResource = "translations"
+
+func translationResourceID(id int64, locale string) string {
+	return fmt.Sprintf("%d/%s", id, locale)
+}
+
+type experienceTranslationPayload struct {
+	Title       *string  `json:"title,omitempty" validate:"omitempty,min=1"`
+	Description []string `json:"description,omitempty"`
+	Company     *string  `json:"company,omitempty" validate:"omitempty,min=1"`
+}
+
+// negotiateLocale resolves the response locale from ?lang= or the
+// Accept-Language header.
+func (app *application) negotiateLocale(r *http.Request) string {
+	return app.locales.Negotiate(r.URL.Query().Get("lang"), r.Header.Get("Accept-Language"))
+}
+
+// translateExperiences overlays the locale's translations onto experiences
+// in place and returns the locales actually served, for Content-Language.
+// Experiences without a translation keep the default locale's content.
+func (app *application) translateExperiences(r *http.Request, locale string, experiences []*store.Experience) ([]string, error) {
+	defaultLocale := app.locales.Default()
+	if locale == defaultLocale || len(experiences) == 0 {
+		return []string{defaultLocale}, nil
+	}
+
+	ids := make([]int64, len(experiences))
+	for i, experience := range experiences {
+		ids[i] = experience.ID
+	}
+
+	translations, err := app.store.Translations.ListByResources(r.Context(), store.ExperiencesResource, ids, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	translated, fallback := false, false
+	for _, experience := range experiences {
+		t, ok := translations[experience.ID]
+		if !ok {
+			fallback = true
+			continue
+		}
+
+		var fields experienceTranslationPayload
+		if err := json.Unmarshal(t.Fields, &fields); err != nil {
+			return nil, err
+		}
+
+		if fields.Title != nil {
+			experience.Title = *fields.Title
+		}
+		if fields.Description != nil {
+			experience.Description = fields.Description
+		}
+		if fields.Company != nil {
+			experience.Company = *fields.Company
+		}
+		translated = true
+	}
+
+	var served []string
+	if translated {
+		served = append(served, locale)
+	}
+	if fallback {
+		served = append(served, defaultLocale)
+	}
+
+	return served, nil
+}
+
+func setContentLanguage(w http.ResponseWriter, locales []string) {
+	w.Header().Set("Content-Language", strings.Join(locales, ", "))
+	w.Header().Add("Vary", "Accept-Language")
+}
+
+func (app *application) listExperienceTranslationsHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := app.experienceIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if !app.ensureExperienceVisible(w, r, chi.URLParam(r, "id")) {
+		return
+	}
+
+	ctx := r.Context()
+
+	translations, err := app.store.Translations.List(ctx, store.ExperiencesResource, id)
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, translations); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) upsertExperienceTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := app.experienceIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	locale := chi.URLParam(r, "locale")
+	if !app.locales.Supported(locale) || locale == app.locales.Default() {
+		app.badRequestResponse(w, r, fmt.Errorf("locale %q is not a supported translation locale", locale))
+		return
+	}
+
+	var payload experienceTranslationPayload
+
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if payload.Title == nil && payload.Description == nil && payload.Company == nil {
+		app.badRequestResponse(w, r, errors.New("at least one of title, description or company is required"))
+		return
+	}
+
+	fields, err := json.Marshal(payload)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	translation := &store.Translation{
+		ResourceType: store.ExperiencesResource,
+		ResourceID:   id,
+		Locale:       locale,
+		Fields:       fields,
+	}
+
+	ctx := r.Context()
+
+	err = app.store.WithTx(ctx, func(tx *store.Storage) error {
+		before, err := tx.Translations.Get(ctx, store.ExperiencesResource, id, locale)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return err
+		}
+		if err := tx.Translations.Upsert(ctx, translation); err != nil {
+			return err
+		}
+		return app.audit(r, tx, "translation.upsert", translationsResource, translationResourceID(id, locale), nilIfMissing(before), translation)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, translation); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) deleteExperienceTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := app.experienceIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	locale := chi.URLParam(r, "locale")
+
+	ctx := r.Context()
+
+	err := app.store.WithTx(ctx, func(tx *store.Storage) error {
+		before, err := tx.Translations.Get(ctx, store.ExperiencesResource, id, locale)
+		if err != nil {
+			return err
+		}
+		if err := tx.Translations.Delete(ctx, store.ExperiencesResource, id, locale); err != nil {
+			return err
+		}
+		return app.audit(r, tx, "translation.delete", translationsResource, translationResourceID(id, locale), before, nil)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, map[string]string{"message": "deleted successfully"}); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// experienceIDParam parses the {id} URL parameter and checks that the
+// experience exists, writing the error response when it does not.
+func (app *application) experienceIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return 0, false
+	}
+
+	if _, err := app.store.Experiences.Get(r.Context(), strconv.FormatInt(id, 10)); err != nil {
+		app.errorResponse(w, r, err)
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/km"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// validationMessages holds the messages for invalid requests in each
+// locale, keyed by validator rule or decode failure. {0} is the field and
+// {1} the rule's parameter.
+var validationMessages = map[string]map[string]string{
+	"en": {
+		"validation_failed": "the request is invalid",
+		"invalid":           "{0} is invalid",
+
+		"required":           "{0} is required",
+		"excluded":           "{0} must not be set",
+		"min_string":         "{0} must be at least {1} characters long",
+		"min_items":          "{0} must contain at least {1} item(s)",
+		"min_number":         "{0} must be {1} or greater",
+		"max_string":         "{0} must be at most {1} characters long",
+		"max_items":          "{0} must contain at most {1} item(s)",
+		"max_number":         "{0} must be {1} or less",
+		"len_string":         "{0} must be exactly {1} characters long",
+		"len_items":          "{0} must contain exactly {1} item(s)",
+		"len_number":         "{0} must equal {1}",
+		"gt":                 "{0} must be greater than {1}",
+		"gte":                "{0} must be {1} or greater",
+		"lt":                 "{0} must be less than {1}",
+		"lte":                "{0} must be {1} or less",
+		"oneof":              "{0} must be one of: {1}",
+		"unique":             "{0} must not contain duplicates",
+		"url":                "{0} must be a valid URL",
+		"http_url":           "{0} must be an http or https URL",
+		"email":              "{0} must be a valid email address",
+		"startswith":         "{0} must start with {1}",
+		"bcp47_language_tag": "{0} must be a BCP 47 language tag",
+
+		"body_empty":      "the request body must not be empty",
+		"body_too_large":  "the request body must not be larger than {0} bytes",
+		"body_malformed":  "the request body contains malformed JSON",
+		"body_wrong_type": "the request body must be {0}",
+		"unknown_field":   "{0} is not a recognized field",
+		"type":            "{0} must be {1}",
+		"type_string":     "a string",
+		"type_integer":    "an integer",
+		"type_number":     "a number",
+		"type_boolean":    "a boolean",
+		"type_array":      "an array",
+		"type_object":     "an object",
+	},
+	"km": {
+		"validation_failed": "សំណើមិនត្រឹមត្រូវ",
+		"invalid":           "{0} មិនត្រឹមត្រូវ",
+
+		"required":           "{0} ត្រូវតែបំពេញ",
+		"excluded":           "{0} មិនត្រូវកំណត់ទេ",
+		"min_string":         "{0} ត្រូវមានយ៉ាងតិច {1} តួអក្សរ",
+		"min_items":          "{0} ត្រូវមានយ៉ាងតិច {1} ធាតុ",
+		"min_number":         "{0} ត្រូវតែធំជាង ឬស្មើ {1}",
+		"max_string":         "{0} ត្រូវមានយ៉ាងច្រើន {1} តួអក្សរ",
+		"max_items":          "{0} ត្រូវមានយ៉ាងច្រើន {1} ធាតុ",
+		"max_number":         "{0} ត្រូវតែតូចជាង ឬស្មើ {1}",
+		"len_string":         "{0} ត្រូវមាន {1} តួអក្សរ",
+		"len_items":          "{0} ត្រូវមាន {1} ធាតុ",
+		"len_number":         "{0} ត្រូវតែស្មើ {1}",
+		"gt":                 "{0} ត្រូវតែធំជាង {1}",
+		"gte":                "{0} ត្រូវតែធំជាង ឬស្មើ {1}",
+		"lt":                 "{0} ត្រូវតែតូចជាង {1}",
+		"lte":                "{0} ត្រូវតែតូចជាង ឬស្មើ {1}",
+		"oneof":              "{0} ត្រូវតែជាតម្លៃមួយក្នុងចំណោម៖ {1}",
+		"unique":             "{0} មិនត្រូវមានតម្លៃស្ទួនគ្នាទេ",
+		"url":                "{0} ត្រូវតែជា URL ត្រឹមត្រូវ",
+		"http_url":           "{0} ត្រូវតែជា URL http ឬ https",
+		"email":              "{0} ត្រូវតែជាអាសយដ្ឋានអ៊ីមែលត្រឹមត្រូវ",
+		"startswith":         "{0} ត្រូវតែចាប់ផ្តើមដោយ {1}",
+		"bcp47_language_tag": "{0} ត្រូវតែជាស្លាកភាសា BCP 47",
+
+		"body_empty":      "តួសំណើមិនអាចទទេបានទេ",
+		"body_too_large":  "តួសំណើមិនអាចធំជាង {0} បៃបានទេ",
+		"body_malformed":  "តួសំណើមាន JSON មិនត្រឹមត្រូវ",
+		"body_wrong_type": "តួសំណើត្រូវតែជា{0}",
+		"unknown_field":   "{0} មិនមែនជាវាលដែលស្គាល់ទេ",
+		"type":            "{0} ត្រូវតែជា{1}",
+		"type_string":     "ខ្សែអក្សរ",
+		"type_integer":    "ចំនួនគត់",
+		"type_number":     "លេខ",
+		"type_boolean":    "តម្លៃ true ឬ false",
+		"type_array":      "អារេ",
+		"type_object":     "វត្ថុ",
+	},
+}
+
+// ruleMessages maps rules that share a message onto its key.
+var ruleMessages = map[string]string{
+	"required_if":      "required",
+	"required_unless":  "required",
+	"required_with":    "required",
+	"required_without": "required",
+	"excluded_if":      "excluded",
+	"excluded_unless":  "excluded",
+	"excluded_with":    "excluded",
+	"excluded_without": "excluded",
+}
+
+// sizedRules depend on whether they constrain a length, a count or a
+// number.
+var sizedRules = map[string]bool{"min": true, "max": true, "len": true}
+
+// enumRules accept the values of a list maintained elsewhere, which a
+// oneof tag would have to repeat. They are reported like oneof.
+var enumRules = map[string][]string{
+	"webhook_event": store.WebhookEvents,
+}
+
+var validationTranslator *ut.UniversalTranslator
+
+// registerValidationMessages names fields after their JSON keys in
+// validation errors and loads the messages into validationTranslator.
+func registerValidationMessages(v *validator.Validate) {
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+
+	english := en.New()
+	validationTranslator = ut.New(english, english, km.New())
+
+	for locale, messages := range validationMessages {
+		trans, _ := validationTranslator.GetTranslator(locale)
+		for key, text := range messages {
+			if err := trans.Add(key, text, false); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+// registerEnumRules adds a validator rule for each of enumRules.
+func registerEnumRules(v *validator.Validate) {
+	for rule, values := range enumRules {
+		err := v.RegisterValidation(rule, func(fl validator.FieldLevel) bool {
+			return slices.Contains(values, fl.Field().String())
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// fieldError describes one invalid field of a request body.
+type fieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// decodeError is returned by readJSON when the body is not a JSON
+// document matching the payload. It records what went wrong as a message
+// key so that the response can be localized.
+type decodeError struct {
+	status int
+	key    string
+	params []string
+	field  string
+	err    error
+}
+
+func (e *decodeError) Error() string { return e.err.Error() }
+func (e *decodeError) Unwrap() error { return e.err }
+
+func newDecodeError(err error) *decodeError {
+	var (
+		syntaxError        *json.SyntaxError
+		unmarshalTypeError *json.UnmarshalTypeError
+		maxBytesError      *http.MaxBytesError
+	)
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return &decodeError{status: http.StatusBadRequest, key: "body_empty", err: err}
+
+	case errors.As(err, &maxBytesError):
+		limit := strconv.FormatInt(maxBytesError.Limit, 10)
+		return &decodeError{status: http.StatusRequestEntityTooLarge, key: "body_too_large", params: []string{limit}, err: err}
+
+	case errors.As(err, &syntaxError), errors.Is(err, io.ErrUnexpectedEOF):
+		return &decodeError{status: http.StatusBadRequest, key: "body_malformed", err: err}
+
+	case errors.As(err, &unmarshalTypeError):
+		kind := "type_" + jsonKind(unmarshalTypeError.Type)
+		if unmarshalTypeError.Field == "" {
+			return &decodeError{status: http.StatusBadRequest, key: "body_wrong_type", params: []string{kind}, err: err}
+		}
+		return &decodeError{status: http.StatusBadRequest, key: "type", params: []string{kind}, field: fieldPath(unmarshalTypeError.Field), err: err}
+	}
+
+	// encoding/json has no error type for unknown fields.
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		field, _ = strconv.Unquote(field)
+		return &decodeError{status: http.StatusBadRequest, key: "unknown_field", field: field, err: err}
+	}
+
+	return &decodeError{status: http.StatusBadRequest, key: "body_malformed", err: err}
+}
+
+// fieldPath writes the path of a field encoding/json failed to decode, such
+// as items.0.count, the way validation errors do: items[0].count.
+func fieldPath(field string) string {
+	var path strings.Builder
+	for i, part := range strings.Split(field, ".") {
+		switch _, err := strconv.Atoi(part); {
+		case err == nil:
+			path.WriteString("[" + part + "]")
+		case i > 0:
+			path.WriteString("." + part)
+		default:
+			path.WriteString(part)
+		}
+	}
+	return path.String()
+}
+
+// jsonKind names the JSON type a Go type is decoded from.
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// translator returns the validation message translator for the locale
+// negotiated for r.
+func (app *application) translator(r *http.Request) ut.Translator {
+	if app.locales == nil {
+		return validationTranslator.GetFallback()
+	}
+	trans, _ := validationTranslator.GetTranslator(app.negotiateLocale(r))
+	return trans
+}
+
+func translate(trans ut.Translator, key string, params ...string) string {
+	message, err := trans.T(key, params...)
+	if err != nil {
+		message, _ = trans.T("invalid", params...)
+	}
+	return message
+}
+
+// validationErrors describes every failed rule of a validator error.
+func validationErrors(trans ut.Translator, errs validator.ValidationErrors) []fieldError {
+	fields := make([]fieldError, len(errs))
+
+	for i, fe := range errs {
+		// Drop the payload type the namespace starts with.
+		_, field, _ := strings.Cut(fe.Namespace(), ".")
+
+		key, param := fe.Tag(), fe.Param()
+		if alias, ok := ruleMessages[key]; ok {
+			key = alias
+		}
+		if values, ok := enumRules[key]; ok {
+			key, param = "oneof", strings.Join(values, " ")
+		}
+		if sizedRules[key] {
+			switch fe.Kind() {
+			case reflect.String:
+				key += "_string"
+			case reflect.Slice, reflect.Array, reflect.Map:
+				key += "_items"
+			default:
+				key += "_number"
+			}
+		}
+
+		fields[i] = fieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Message: translate(trans, key, field, param),
+		}
+	}
+
+	return fields
+}
+
+// invalidRequestError describes a body that could not be decoded or failed
+// validation in the caller's language. It returns nil for other errors.
+func (app *application) invalidRequestError(r *http.Request, err error) *appError {
+	var (
+		validationErrs validator.ValidationErrors
+		decodeErr      *decodeError
+	)
+
+	trans := app.translator(r)
+
+	switch {
+	case errors.As(err, &validationErrs):
+		return &appError{
+			Code:   codeValidationFailed,
+			Status: http.StatusBadRequest,
+			Detail: translate(trans, "validation_failed"),
+			Fields: validationErrors(trans, validationErrs),
+			Err:    err,
+		}
+
+	case errors.As(err, &decodeErr):
+		e := &appError{Code: codeInvalidBody, Status: decodeErr.status, Err: err}
+		if e.Status == http.StatusRequestEntityTooLarge {
+			e.Code = codeBodyTooLarge
+		}
+
+		params := make([]string, len(decodeErr.params))
+		for i, p := range decodeErr.params {
+			params[i] = p
+			if strings.HasPrefix(p, "type_") {
+				params[i] = translate(trans, p)
+			}
+		}
+
+		if decodeErr.field == "" {
+			e.Detail = translate(trans, decodeErr.key, params...)
+			return e
+		}
+
+		e.Detail = translate(trans, "validation_failed")
+		e.Fields = []fieldError{{
+			Field:   decodeErr.field,
+			Rule:    decodeErr.key,
+			Message: translate(trans, decodeErr.key, append([]string{decodeErr.field}, params...)...),
+		}}
+		return e
+	}
+
+	return nil
+}
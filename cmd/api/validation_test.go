@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vatanak10/portfolio-backend/internal/i18n"
+)
+
+type testItem struct {
+	Name  string `json:"name" validate:"required,max=5"`
+	Count int    `json:"count" validate:"min=1"`
+}
+
+type testPayload struct {
+	Title  string     `json:"title" validate:"min=3"`
+	Tags   []string   `json:"tags" validate:"min=1,dive,oneof=go sql"`
+	Items  []testItem `json:"items" validate:"dive"`
+	Events []string   `json:"events" validate:"dive,webhook_event"`
+}
+
+// decode runs body through readJSON and invalidRequestError as a handler
+// would.
+func decode(app *application, target, body string) *appError {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+
+	var payload testPayload
+	err := readJSON(w, r, &payload)
+	if err == nil {
+		err = Validate.Struct(payload)
+	}
+	if err == nil {
+		return nil
+	}
+	return app.invalidRequestError(r, err)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+		detail string
+		field  string
+		rule   string
+	}{
+		{name: "empty", body: "", status: http.StatusBadRequest, code: codeInvalidBody, detail: "the request body must not be empty"},
+		{name: "too large", body: `{"title":"` + strings.Repeat("a", 1<<20) + `"}`, status: http.StatusRequestEntityTooLarge, code: codeBodyTooLarge,
+			detail: "the request body must not be larger than 1048578 bytes"},
+		{name: "malformed", body: `{"title":`, status: http.StatusBadRequest, code: codeInvalidBody, detail: "the request body contains malformed JSON"},
+		{name: "wrong top-level type", body: `["title"]`, status: http.StatusBadRequest, code: codeInvalidBody, detail: "the request body must be an object"},
+		{name: "unknown field", body: `{"titel":"Engineer"}`, status: http.StatusBadRequest, code: codeInvalidBody, field: "titel", rule: "unknown_field"},
+		{name: "wrong nested type", body: `{"items":[{"name":"a","count":"two"}]}`, status: http.StatusBadRequest, code: codeInvalidBody,
+			field: "items[0].count", rule: "type"},
+	}
+
+	app := &application{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := decode(app, "/", tt.body)
+			if e == nil {
+				t.Fatal("the body was accepted")
+			}
+
+			if e.Status != tt.status || e.Code != tt.code {
+				t.Errorf("got %d %s, want %d %s", e.Status, e.Code, tt.status, tt.code)
+			}
+			if tt.detail != "" && e.Detail != tt.detail {
+				t.Errorf("detail = %q, want %q", e.Detail, tt.detail)
+			}
+			if tt.field != "" {
+				if len(e.Fields) != 1 || e.Fields[0].Field != tt.field || e.Fields[0].Rule != tt.rule {
+					t.Errorf("fields = %+v, want %s failing %s", e.Fields, tt.field, tt.rule)
+				}
+			}
+		})
+	}
+}
+
+func TestNewDecodeErrorWrapsCause(t *testing.T) {
+	cause := errors.New("connection reset")
+	if err := newDecodeError(cause); !errors.Is(err, cause) || err.key != "body_malformed" {
+		t.Errorf("newDecodeError(%v) = %s, %v", cause, err.key, err)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	body := `{"title":"Go","tags":[],"items":[{"name":"toolong","count":1},{"name":"ok","count":0}],"events":["experience.created","experience.renamed"]}`
+
+	e := decode(&application{}, "/", body)
+	if e == nil {
+		t.Fatal("the body was accepted")
+	}
+	if e.Status != http.StatusBadRequest || e.Code != codeValidationFailed || e.Detail != "the request is invalid" {
+		t.Fatalf("got %d %s %q", e.Status, e.Code, e.Detail)
+	}
+
+	want := []fieldError{
+		{Field: "title", Rule: "min", Message: "title must be at least 3 characters long"},
+		{Field: "tags", Rule: "min", Message: "tags must contain at least 1 item(s)"},
+		{Field: "items[0].name", Rule: "max", Message: "items[0].name must be at most 5 characters long"},
+		{Field: "items[1].count", Rule: "min", Message: "items[1].count must be 1 or greater"},
+		{Field: "events[1]", Rule: "webhook_event",
+			Message: "events[1] must be one of: experience.created experience.updated experience.deleted experience.restored"},
+	}
+	if !slices.Equal(e.Fields, want) {
+		t.Errorf("fields =\n%+v\nwant\n%+v", e.Fields, want)
+	}
+}
+
+func TestValidationErrorsInKhmer(t *testing.T) {
+	locales, err := i18n.NewNegotiator("en", []string{"en", "km"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &application{locales: locales}
+
+	e := decode(app, "/?lang=km", `{"title":"Go","tags":["go"]}`)
+	if e == nil {
+		t.Fatal("the body was accepted")
+	}
+
+	if e.Detail != "សំណើមិនត្រឹមត្រូវ" {
+		t.Errorf("detail = %q, want the Khmer message", e.Detail)
+	}
+	if len(e.Fields) != 1 || e.Fields[0].Message != "title ត្រូវមានយ៉ាងតិច 3 តួអក្សរ" {
+		t.Errorf("fields = %+v, want the Khmer message for title", e.Fields)
+	}
+
+	if e := decode(app, "/?lang=km", ""); e == nil || e.Detail != "តួសំណើមិនអាចទទេបានទេ" {
+		t.Errorf("empty body = %+v, want the Khmer message", e)
+	}
+}
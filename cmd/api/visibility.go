@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vatanak10/portfolio-backend/internal/share"
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// shareTokenParam is the query parameter carrying a share token.
+const shareTokenParam = "share"
+
+type experienceShareResponse struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// listFilter limits anonymous listings to public experiences; authenticated
+// callers see every visibility level.
+func listFilter(r *http.Request) store.ExperienceFilter {
+	if getPrincipal(r) != "" {
+		return store.ExperienceFilter{}
+	}
+	return store.ExperienceFilter{Visibility: []string{store.VisibilityPublic}}
+}
+
+// canView reports whether the caller may fetch experience by ID. Public and
+// unlisted experiences are open to anyone, private ones to authenticated
+// callers and to requests carrying a valid share token for that experience.
+func (app *application) canView(r *http.Request, experience *store.Experience) bool {
+	if experience.Visibility != store.VisibilityPrivate || getPrincipal(r) != "" {
+		return true
+	}
+
+	token := r.URL.Query().Get(shareTokenParam)
+	if token == "" {
+		return false
+	}
+
+	claims, err := app.shares.Verify(token)
+	if err != nil {
+		app.requestLogger(r).Infow("rejected share token", "error", err.Error())
+		return false
+	}
+
+	return claims.Subject == shareSubject(store.ExperiencesResource, experience.ID)
+}
+
+// ensureExperienceVisible responds with 404 and returns false when the
+// caller may not see the experience with the given ID, so that hidden
+// entries are indistinguishable from missing ones.
+func (app *application) ensureExperienceVisible(w http.ResponseWriter, r *http.Request, id string) bool {
+	if getPrincipal(r) != "" {
+		return true
+	}
+
+	experience, err := app.store.Experiences.Get(r.Context(), id)
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return false
+	}
+
+	if !app.canView(r, experience) {
+		app.notFoundResponse(w, r, store.ErrNotFound)
+		return false
+	}
+
+	return true
+}
+
+func shareSubject(resource string, id int64) string {
+	return resource + "/" + strconv.FormatInt(id, 10)
+}
+
+// createExperienceShareHandler issues a signed link that shows one
+// experience, whatever its visibility, until it expires. The lifetime
+// defaults to SHARE_TTL and can be shortened or extended with ?expires_in=.
+func (app *application) createExperienceShareHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	ttl := app.config.Share.TTL
+	if value := r.URL.Query().Get("expires_in"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			app.badRequestResponse(w, r, fmt.Errorf("query parameter expires_in must be a positive duration such as 72h"))
+			return
+		}
+		ttl = d
+	}
+
+	ctx := r.Context()
+
+	experience, err := app.store.Experiences.Get(ctx, id)
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	claims := share.Claims{
+		Subject:   shareSubject(store.ExperiencesResource, experience.ID),
+		ExpiresAt: time.Now().Add(ttl).Truncate(time.Second),
+	}
+	token := app.shares.Sign(claims)
+
+	// Nothing is stored for the token, so the audit entry is its only
+	// record; it is not handed out unless the entry is written.
+	if err := app.audit(r, app.store, "experience.share", store.ExperiencesResource, id, nil, claims); err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	response := experienceShareResponse{
+		Token:     token,
+		URL:       fmt.Sprintf("/v1/experiences/%d?%s=%s", experience.ID, shareTokenParam, token),
+		ExpiresAt: claims.ExpiresAt.UTC(),
+	}
+
+	if err := app.jsonResponse(w, http.StatusCreated, response); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestChangesRequireCredentials(t *testing.T) {
+	app := &application{logger: zap.NewNop().Sugar()}
+	mux := app.mount()
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/v1/experiences"},
+		{http.MethodPost, "/v1/experiences/batch"},
+		{http.MethodPut, "/v1/experiences/order"},
+		{http.MethodPut, "/v1/experiences/1"},
+		{http.MethodDelete, "/v1/experiences/1"},
+		{http.MethodPost, "/v1/experiences/1/share"},
+		{http.MethodPut, "/v1/experiences/1/translations/km"},
+		{http.MethodDelete, "/v1/experiences/1/translations/km"},
+		{http.MethodPost, "/v1/experiences/1/revisions/2/revert"},
+		{http.MethodPost, "/v1/import/resume"},
+	}
+
+	for _, route := range routes {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(route.method, route.path, nil))
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s without credentials = %d, want %d", route.method, route.path, w.Code, http.StatusUnauthorized)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// webhookSecretPrefix marks generated webhook signing secrets.
+const webhookSecretPrefix = "whsec_"
+
+type webhookPayload struct {
+	URL         string   `json:"url" validate:"required,http_url,max=2048"`
+	Events      []string `json:"events" validate:"required,min=1,unique,dive,webhook_event"`
+	Description string   `json:"description" validate:"max=255"`
+	Active      *bool    `json:"active"`
+}
+
+// webhookResponse reveals the signing secret, which is only done once when
+// the webhook is created.
+type webhookResponse struct {
+	*store.Webhook
+	Secret string `json:"secret"`
+}
+
+func (app *application) createWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var payload webhookPayload
+
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	webhook := &store.Webhook{
+		URL:    payload.URL,
+		Secret: webhookSecretPrefix + hex.EncodeToString(secret),
+		Events: payload.Events,
+		Active: payload.Active == nil || *payload.Active,
+	}
+	if payload.Description != "" {
+		webhook.Description = &payload.Description
+	}
+
+	err := app.store.WithTx(r.Context(), func(tx *store.Storage) error {
+		if err := tx.Webhooks.Create(r.Context(), webhook); err != nil {
+			return err
+		}
+		return app.audit(r, tx, "webhook.create", store.WebhooksResource, strconv.FormatInt(webhook.ID, 10), nil, webhook)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusCreated, webhookResponse{Webhook: webhook, Secret: webhook.Secret}); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) listWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := app.store.Webhooks.List(r.Context())
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, webhooks); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) getWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := app.webhookIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := app.store.Webhooks.Get(r.Context(), id)
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, webhook); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// updateWebhookHandler replaces a webhook's URL, events and description.
+// Active is left as it is when omitted.
+func (app *application) updateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := app.webhookIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var payload webhookPayload
+
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	before, err := app.store.Webhooks.Get(ctx, id)
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	webhook := *before
+	webhook.URL = payload.URL
+	webhook.Events = payload.Events
+	webhook.Description = nil
+	if payload.Description != "" {
+		webhook.Description = &payload.Description
+	}
+	if payload.Active != nil {
+		webhook.Active = *payload.Active
+	}
+
+	err = app.store.WithTx(ctx, func(tx *store.Storage) error {
+		if err := tx.Webhooks.Update(ctx, &webhook); err != nil {
+			return err
+		}
+		return app.audit(r, tx, "webhook.update", store.WebhooksResource, strconv.FormatInt(id, 10), before, &webhook)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, &webhook); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) deleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := app.webhookIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	err := app.store.WithTx(r.Context(), func(tx *store.Storage) error {
+		before, err := tx.Webhooks.Get(r.Context(), id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Webhooks.Delete(r.Context(), id); err != nil {
+			return err
+		}
+		return app.audit(r, tx, "webhook.delete", store.WebhooksResource, strconv.FormatInt(id, 10), before, nil)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, map[string]string{"message": "deleted successfully"}); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) listWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := app.webhookIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+
+	if _, err := app.store.Webhooks.Get(ctx, id); err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	deliveries, err := app.store.Webhooks.ListDeliveries(ctx, id, store.NewPaginationParams(limit, offset))
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, deliveries); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) getWebhookDeliveryHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := app.webhookIDParam(w, r)
+	if !ok {
+		return
+	}
+	deliveryID, ok := app.webhookDeliveryIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := app.store.Webhooks.GetDelivery(r.Context(), id, deliveryID)
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, delivery); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// redeliverWebhookHandler queues a fresh copy of a delivery, whatever the
+// state of the original, and returns it.
+func (app *application) redeliverWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := app.webhookIDParam(w, r)
+	if !ok {
+		return
+	}
+	deliveryID, ok := app.webhookDeliveryIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var delivery *store.WebhookDelivery
+	err := app.store.WithTx(r.Context(), func(tx *store.Storage) error {
+		var err error
+		delivery, err = tx.Webhooks.Redeliver(r.Context(), id, deliveryID)
+		if err != nil {
+			return err
+		}
+		return app.audit(r, tx, "webhook.redeliver", store.WebhooksResource, strconv.FormatInt(id, 10), nil, delivery)
+	})
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusAccepted, delivery); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func (app *application) webhookIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return 0, false
+	}
+	return id, true
+}
+
+func (app *application) webhookDeliveryIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := pathID(r, "delivery")
+	if err != nil {
+		app.errorResponse(w, r, err)
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS translations (
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id BIGINT NOT NULL,
+    locale VARCHAR(35) NOT NULL,
+    fields JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (resource_type, resource_id, locale)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS translations;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS experience_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    experience_id INTEGER NOT NULL REFERENCES experiences (id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    snapshot JSONB NOT NULL,
+    actor VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (experience_id, revision)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS experience_revisions;
+-- +goose StatementEnd
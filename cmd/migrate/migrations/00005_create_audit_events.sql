@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    actor VARCHAR(255),
+    ip VARCHAR(64),
+    request_id VARCHAR(255),
+    action VARCHAR(100) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(100),
+    before_hash CHAR(64),
+    after_hash CHAR(64)
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_occurred_at_idx ON audit_events (occurred_at);
+CREATE INDEX IF NOT EXISTS audit_events_actor_idx ON audit_events (actor, occurred_at);
+CREATE INDEX IF NOT EXISTS audit_events_resource_idx ON audit_events (resource_type, resource_id, occurred_at);
+
+-- Audit events are append-only: rows may be inserted and expired by the
+-- retention job, but never modified.
+CREATE OR REPLACE FUNCTION audit_events_prevent_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_update
+    BEFORE UPDATE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_prevent_update();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS audit_events_prevent_update();
+-- +goose StatementEnd
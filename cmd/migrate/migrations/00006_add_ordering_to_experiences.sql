@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE experiences
+    ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Start from the order a reader would expect: most recent role first.
+UPDATE experiences e SET position = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY start_date DESC, id) AS position
+    FROM experiences
+) ordered
+WHERE e.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS experiences_display_order_idx
+    ON experiences (pinned DESC, position, start_date DESC)
+    WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS experiences_display_order_idx;
+ALTER TABLE experiences DROP COLUMN IF EXISTS pinned, DROP COLUMN IF EXISTS position;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE experiences
+    ADD COLUMN IF NOT EXISTS visibility VARCHAR(16) NOT NULL DEFAULT 'public'
+        CONSTRAINT experiences_visibility_check CHECK (visibility IN ('public', 'unlisted', 'private'));
+
+DROP INDEX IF EXISTS experiences_display_order_idx;
+CREATE INDEX IF NOT EXISTS experiences_display_order_idx
+    ON experiences (visibility, pinned DESC, position, start_date DESC)
+    WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS experiences_display_order_idx;
+CREATE INDEX IF NOT EXISTS experiences_display_order_idx
+    ON experiences (pinned DESC, position, start_date DESC)
+    WHERE deleted_at IS NULL;
+ALTER TABLE experiences DROP COLUMN IF EXISTS visibility;
+-- +goose StatementEnd
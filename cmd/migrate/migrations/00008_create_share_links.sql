@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS share_links (
+    id BIGSERIAL PRIMARY KEY,
+    scope VARCHAR(16) NOT NULL CHECK (scope IN ('resume', 'experiences')),
+    resource_ids BIGINT[] NOT NULL DEFAULT '{}',
+    label VARCHAR(255),
+    password_hash VARCHAR(72),
+    max_uses INTEGER CHECK (max_uses > 0),
+    uses INTEGER NOT NULL DEFAULT 0,
+    -- Compared against NOW() on every view, so stored with a time zone.
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS share_link_views (
+    id BIGSERIAL PRIMARY KEY,
+    share_link_id BIGINT NOT NULL REFERENCES share_links (id) ON DELETE CASCADE,
+    viewed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ip VARCHAR(64),
+    user_agent TEXT
+);
+
+CREATE INDEX IF NOT EXISTS share_link_views_link_idx ON share_link_views (share_link_id, viewed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS share_link_views;
+DROP TABLE IF EXISTS share_links;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Events are partitioned by month; the API creates upcoming partitions
+-- ahead of time and anything outside them lands in the default partition.
+CREATE TABLE IF NOT EXISTS analytics_events (
+    id BIGSERIAL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('page_view', 'click')),
+    path TEXT NOT NULL,
+    referrer TEXT,
+    utm_source VARCHAR(255),
+    utm_medium VARCHAR(255),
+    utm_campaign VARCHAR(255),
+    utm_term VARCHAR(255),
+    utm_content VARCHAR(255),
+    resource_type VARCHAR(50),
+    resource_id BIGINT,
+    visitor_hash CHAR(64) NOT NULL,
+    PRIMARY KEY (id, occurred_at)
+) PARTITION BY RANGE (occurred_at);
+
+CREATE TABLE IF NOT EXISTS analytics_events_default PARTITION OF analytics_events DEFAULT;
+
+CREATE INDEX IF NOT EXISTS analytics_events_occurred_at_idx ON analytics_events (occurred_at);
+CREATE INDEX IF NOT EXISTS analytics_events_resource_idx ON analytics_events (resource_type, resource_id, occurred_at);
+
+-- One random salt per UTC day for hashing visitors. Old salts are deleted so
+-- that hashes from previous days can no longer be linked to an IP address.
+CREATE TABLE IF NOT EXISTS analytics_salts (
+    day DATE PRIMARY KEY,
+    salt BYTEA NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_salts;
+DROP TABLE IF EXISTS analytics_events;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Domain events, written in the same transaction as the change they
+-- describe and dispatched to subscribers by the event bus. An event with
+-- processed_at and last_error set was given up on.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(64) NOT NULL,
+    resource_type VARCHAR(64) NOT NULL,
+    resource_id VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    actor VARCHAR(255),
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    processed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (next_attempt_at, id) WHERE processed_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_processed_idx ON outbox (processed_at) WHERE processed_at IS NOT NULL;
+
+-- Webhook deliveries are now queued by an outbox subscriber; event_id makes
+-- that idempotent when an event is dispatched more than once.
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS event_id BIGINT REFERENCES outbox (id) ON DELETE SET NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_deliveries_event_idx ON webhook_deliveries (webhook_id, event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS webhook_deliveries_event_idx;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS event_id;
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS skills (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    category VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS projects (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    url VARCHAR(2048),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS experience_skills (
+    experience_id INTEGER NOT NULL REFERENCES experiences (id) ON DELETE CASCADE,
+    skill_id BIGINT NOT NULL REFERENCES skills (id) ON DELETE CASCADE,
+    PRIMARY KEY (experience_id, skill_id)
+);
+
+CREATE TABLE IF NOT EXISTS project_skills (
+    project_id BIGINT NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    skill_id BIGINT NOT NULL REFERENCES skills (id) ON DELETE CASCADE,
+    PRIMARY KEY (project_id, skill_id)
+);
+
+-- The primary keys serve lookups by experience and by project; these serve
+-- the reverse direction.
+CREATE INDEX IF NOT EXISTS experience_skills_skill_idx ON experience_skills (skill_id);
+CREATE INDEX IF NOT EXISTS project_skills_skill_idx ON project_skills (skill_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS project_skills;
+DROP TABLE IF EXISTS experience_skills;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS skills;
+-- +goose StatementEnd
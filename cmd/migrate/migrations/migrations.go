@@ -0,0 +1,9 @@
+package migrations
+
+import "embed"
+
+// FS holds the SQL migrations so that binaries can apply them without the
+// source tree or an external goose install.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,70 @@
+// Package analytics ingests privacy-friendly page view and click events:
+// visitors are identified only by a hash that changes every day, and
+// events are written to the database in batches off the request path.
+package analytics
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// SaltSource provides the shared salt for a UTC day.
+type SaltSource interface {
+	DailySalt(ctx context.Context, t time.Time) ([]byte, error)
+}
+
+// VisitorHasher turns an IP address and user agent into an opaque visitor
+// ID. The salt rotates at midnight UTC and old salts are discarded, so the
+// same person gets an unrelated ID each day and IDs cannot be reversed.
+type VisitorHasher struct {
+	source SaltSource
+	now    func() time.Time
+
+	mu   sync.Mutex
+	day  string
+	salt []byte
+}
+
+func NewVisitorHasher(source SaltSource) *VisitorHasher {
+	return &VisitorHasher{source: source, now: time.Now}
+}
+
+// Hash returns the visitor ID for ip and userAgent today.
+func (h *VisitorHasher) Hash(ctx context.Context, ip, userAgent string) (string, error) {
+	salt, err := h.currentSalt(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.New()
+	sum.Write(salt)
+	sum.Write([]byte(ip))
+	sum.Write([]byte{0})
+	sum.Write([]byte(userAgent))
+
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
+
+func (h *VisitorHasher) currentSalt(ctx context.Context) ([]byte, error) {
+	now := h.now().UTC()
+	day := now.Format(time.DateOnly)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.day == day {
+		return h.salt, nil
+	}
+
+	salt, err := h.source.DailySalt(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	h.day, h.salt = day, salt
+
+	return salt, nil
+}
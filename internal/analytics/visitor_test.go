@@ -0,0 +1,51 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// daySalts derives a salt from the day and counts how often it is asked.
+type daySalts struct{ calls int }
+
+func (s *daySalts) DailySalt(ctx context.Context, t time.Time) ([]byte, error) {
+	s.calls++
+	return []byte(t.Format(time.DateOnly)), nil
+}
+
+func TestVisitorHasherRotatesDaily(t *testing.T) {
+	ctx := context.Background()
+	salts := &daySalts{}
+	h := NewVisitorHasher(salts)
+
+	now := time.Date(2026, 3, 14, 23, 59, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	first, err := h.Hash(ctx, "192.0.2.1", "Firefox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := h.Hash(ctx, "192.0.2.1", "Firefox"); again != first {
+		t.Errorf("the same visitor got %s and %s on one day", first, again)
+	}
+	if other, _ := h.Hash(ctx, "192.0.2.2", "Firefox"); other == first {
+		t.Error("two visitors got the same ID")
+	}
+	if salts.calls != 1 {
+		t.Errorf("the salt was loaded %d times in one day, want once", salts.calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	next, err := h.Hash(ctx, "192.0.2.1", "Firefox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next == first {
+		t.Error("the visitor ID did not change at midnight UTC")
+	}
+	if salts.calls != 2 {
+		t.Errorf("the salt was loaded %d times over two days, want twice", salts.calls)
+	}
+}
@@ -0,0 +1,126 @@
+package analytics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// flushTimeout bounds a single batch insert.
+const flushTimeout = 10 * time.Second
+
+// Sink persists a batch of events.
+type Sink interface {
+	InsertEvents(ctx context.Context, events []*store.AnalyticsEvent) error
+}
+
+// Writer buffers events in memory and writes them to a Sink in batches,
+// when a batch fills up or on every flush interval, whichever comes first.
+// Enqueue never blocks the caller: when the buffer is full the event is
+// dropped. Close flushes whatever is buffered.
+type Writer struct {
+	sink      Sink
+	logger    *zap.SugaredLogger
+	batchSize int
+	interval  time.Duration
+
+	mu     sync.RWMutex
+	closed bool
+	events chan *store.AnalyticsEvent
+	done   chan struct{}
+}
+
+func NewWriter(sink Sink, logger *zap.SugaredLogger, bufferSize, batchSize int, interval time.Duration) *Writer {
+	return &Writer{
+		sink:      sink,
+		logger:    logger,
+		batchSize: batchSize,
+		interval:  interval,
+		events:    make(chan *store.AnalyticsEvent, bufferSize),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start runs the background flusher. It returns immediately.
+func (w *Writer) Start() {
+	go w.run()
+}
+
+// Enqueue buffers e and reports whether it was accepted.
+func (w *Writer) Enqueue(e *store.AnalyticsEvent) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return false
+	}
+
+	select {
+	case w.events <- e:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops accepting events and waits until the buffered ones have been
+// written or ctx is done.
+func (w *Writer) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.events)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	batch := make([]*store.AnalyticsEvent, 0, w.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+		defer cancel()
+
+		if err := w.sink.InsertEvents(ctx, batch); err != nil {
+			w.logger.Errorw("writing analytics events", "count", len(batch), "error", err.Error())
+		}
+		batch = make([]*store.AnalyticsEvent, 0, w.batchSize)
+	}
+
+	for {
+		select {
+		case e, ok := <-w.events:
+			if !ok {
+				flush()
+				return
+			}
+
+			batch = append(batch, e)
+			if len(batch) >= w.batchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
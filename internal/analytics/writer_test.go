@@ -0,0 +1,88 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// channelSink hands every batch it is given to the test.
+type channelSink chan []*store.AnalyticsEvent
+
+func (s channelSink) InsertEvents(ctx context.Context, events []*store.AnalyticsEvent) error {
+	s <- events
+	return nil
+}
+
+func receive(t *testing.T, sink channelSink) []*store.AnalyticsEvent {
+	t.Helper()
+	select {
+	case batch := <-sink:
+		return batch
+	case <-time.After(time.Second):
+		t.Fatal("no batch was written")
+		return nil
+	}
+}
+
+func TestWriterFlushesFullBatches(t *testing.T) {
+	sink := make(channelSink, 1)
+	w := NewWriter(sink, zap.NewNop().Sugar(), 10, 2, time.Hour)
+	w.Start()
+	defer w.Close(context.Background())
+
+	w.Enqueue(&store.AnalyticsEvent{Path: "/a"})
+	w.Enqueue(&store.AnalyticsEvent{Path: "/b"})
+
+	if batch := receive(t, sink); len(batch) != 2 || batch[0].Path != "/a" || batch[1].Path != "/b" {
+		t.Errorf("batch = %+v, want /a and /b", batch)
+	}
+}
+
+func TestWriterFlushesOnInterval(t *testing.T) {
+	sink := make(channelSink, 1)
+	w := NewWriter(sink, zap.NewNop().Sugar(), 10, 100, 10*time.Millisecond)
+	w.Start()
+	defer w.Close(context.Background())
+
+	w.Enqueue(&store.AnalyticsEvent{Path: "/a"})
+
+	if batch := receive(t, sink); len(batch) != 1 {
+		t.Errorf("batch has %d events, want 1", len(batch))
+	}
+}
+
+func TestWriterFlushesOnClose(t *testing.T) {
+	sink := make(channelSink, 1)
+	w := NewWriter(sink, zap.NewNop().Sugar(), 10, 100, time.Hour)
+	w.Start()
+
+	for range 3 {
+		w.Enqueue(&store.AnalyticsEvent{Path: "/a"})
+	}
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if batch := receive(t, sink); len(batch) != 3 {
+		t.Errorf("batch has %d events, want 3", len(batch))
+	}
+	if w.Enqueue(&store.AnalyticsEvent{Path: "/a"}) {
+		t.Error("Enqueue() after Close = true, want false")
+	}
+}
+
+func TestWriterDropsWhenFull(t *testing.T) {
+	w := NewWriter(make(channelSink), zap.NewNop().Sugar(), 1, 100, time.Hour)
+
+	if !w.Enqueue(&store.AnalyticsEvent{Path: "/a"}) {
+		t.Fatal("Enqueue() into an empty buffer = false, want true")
+	}
+	if w.Enqueue(&store.AnalyticsEvent{Path: "/b"}) {
+		t.Error("Enqueue() into a full buffer = true, want false")
+	}
+}
@@ -0,0 +1,16 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache is a byte-oriented key/value cache shared by the store decorators.
+type Cache interface {
+	// Get returns the value stored at key and whether it was present.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// Incr atomically increments the integer at key, starting from zero.
+	Incr(ctx context.Context, key string) (int64, error)
+}
@@ -0,0 +1,215 @@
+// Package cachetest provides a stand-in Redis server for tests.
+package cachetest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Redis is an in-memory server speaking enough of the Redis protocol
+// (RESP2) for the cache package: PING, GET, SET with EX or PX, DEL and
+// INCR. Other commands, including the client's HELLO handshake, are
+// refused the way an older server would.
+type Redis struct {
+	listener net.Listener
+
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+	calls   map[string]int
+}
+
+// NewRedis starts a server that is shut down when t ends.
+func NewRedis(t testing.TB) *Redis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Redis{
+		listener: listener,
+		values:   map[string]string{},
+		expires:  map[string]time.Time{},
+		calls:    map[string]int{},
+	}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return s
+}
+
+// URL is the address to pass to cache.NewRedis.
+func (s *Redis) URL() string {
+	return "redis://" + s.listener.Addr().String() + "/0"
+}
+
+// Keys returns the stored keys that have not expired.
+func (s *Redis) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for key := range s.values {
+		if s.live(key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Calls reports how often command (in upper case) was received.
+func (s *Redis) Calls(command string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[command]
+}
+
+func (s *Redis) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Redis) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		s.exec(w, args)
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected %q", line)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+		if err != nil {
+			return nil, err
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+
+	return args, nil
+}
+
+func (s *Redis) exec(w *bufio.Writer, args []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	command := strings.ToUpper(args[0])
+	s.calls[command]++
+
+	switch {
+	case command == "PING":
+		w.WriteString("+PONG\r\n")
+
+	case command == "GET" && len(args) == 2:
+		if !s.live(args[1]) {
+			w.WriteString("$-1\r\n")
+			return
+		}
+		writeBulk(w, s.values[args[1]])
+
+	case command == "SET" && len(args) >= 3:
+		key := args[1]
+		s.values[key] = args[2]
+		delete(s.expires, key)
+		if len(args) == 5 {
+			n, _ := strconv.Atoi(args[4])
+			unit := time.Second
+			if strings.EqualFold(args[3], "PX") {
+				unit = time.Millisecond
+			}
+			s.expires[key] = time.Now().Add(time.Duration(n) * unit)
+		}
+		w.WriteString("+OK\r\n")
+
+	case command == "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if s.live(key) {
+				n++
+			}
+			delete(s.values, key)
+			delete(s.expires, key)
+		}
+		fmt.Fprintf(w, ":%d\r\n", n)
+
+	case command == "INCR" && len(args) == 2:
+		n := int64(0)
+		if s.live(args[1]) {
+			var err error
+			if n, err = strconv.ParseInt(s.values[args[1]], 10, 64); err != nil {
+				w.WriteString("-ERR value is not an integer or out of range\r\n")
+				return
+			}
+		}
+		n++
+		s.values[args[1]] = strconv.FormatInt(n, 10)
+		fmt.Fprintf(w, ":%d\r\n", n)
+
+	default:
+		fmt.Fprintf(w, "-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+// live reports whether key holds an unexpired value, dropping it if not.
+func (s *Redis) live(key string) bool {
+	if _, ok := s.values[key]; !ok {
+		return false
+	}
+	if at, ok := s.expires[key]; ok && !time.Now().Before(at) {
+		delete(s.values, key)
+		delete(s.expires, key)
+		return false
+	}
+	return true
+}
+
+func writeBulk(w *bufio.Writer, value string) {
+	fmt.Fprintf(w, "$%d\r\n%s\r\n", len(value), value)
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Memory is an in-process LRU cache bounded to a number of entries. It is
+// only shared by goroutines of one instance; use Redis across replicas.
+type Memory struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+
+	// Counters live outside the LRU so that they are never evicted.
+	counters map[string]int64
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewMemory(size int) *Memory {
+	return &Memory{
+		size:     size,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element, size),
+		counters: map[string]int64{},
+	}
+}
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if n, ok := m.counters[key]; ok {
+		return []byte(strconv.FormatInt(n, 10)), true, nil
+	}
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := el.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		m.remove(el)
+		return nil, false, nil
+	}
+
+	m.order.MoveToFront(el)
+	return entry.value, true, nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.set(key, value, ttl)
+	return nil
+}
+
+func (m *Memory) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.counters, key)
+		if el, ok := m.entries[key]; ok {
+			m.remove(el)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Incr(_ context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counters[key]++
+	return m.counters[key], nil
+}
+
+func (m *Memory) set(key string, value []byte, ttl time.Duration) {
+	entry := &memoryEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	if el, ok := m.entries[key]; ok {
+		el.Value = entry
+		m.order.MoveToFront(el)
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(entry)
+
+	for m.order.Len() > m.size {
+		m.remove(m.order.Back())
+	}
+}
+
+func (m *Memory) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.entries, el.Value.(*memoryEntry).key)
+}
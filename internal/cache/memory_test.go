@@ -0,0 +1,53 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vatanak10/portfolio-backend/internal/cache"
+)
+
+func TestMemoryEvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.NewMemory(2)
+	ctx := context.Background()
+
+	c.Set(ctx, "a", []byte("1"), 0)
+	c.Set(ctx, "b", []byte("2"), 0)
+	c.Get(ctx, "a")
+	c.Set(ctx, "c", []byte("3"), 0)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("b was kept although it was least recently used")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := c.Get(ctx, key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+}
+
+func TestMemoryExpiry(t *testing.T) {
+	c := cache.NewMemory(2)
+	ctx := context.Background()
+
+	c.Set(ctx, "a", []byte("1"), 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("Get returned an expired entry")
+	}
+}
+
+func TestMemoryCountersAreNotEvicted(t *testing.T) {
+	c := cache.NewMemory(1)
+	ctx := context.Background()
+
+	c.Incr(ctx, "generation")
+	c.Set(ctx, "a", []byte("1"), 0)
+	c.Set(ctx, "b", []byte("2"), 0)
+
+	if v, ok, _ := c.Get(ctx, "generation"); !ok || string(v) != "1" {
+		t.Errorf("Get(generation) = %q, %v; want 1, true", v, ok)
+	}
+}
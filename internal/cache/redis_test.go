@@ -0,0 +1,70 @@
+package cache_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vatanak10/portfolio-backend/internal/cache"
+	"github.com/vatanak10/portfolio-backend/internal/cache/cachetest"
+)
+
+func newRedis(t *testing.T) (*cache.Redis, *cachetest.Redis) {
+	t.Helper()
+
+	server := cachetest.NewRedis(t)
+	c, err := cache.NewRedis(context.Background(), server.URL(), "test:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return c, server
+}
+
+func TestRedis(t *testing.T) {
+	c, server := newRedis(t)
+	ctx := context.Background()
+
+	if _, ok, err := c.Get(ctx, "missing"); ok || err != nil {
+		t.Fatalf("Get(missing) = %v, %v", ok, err)
+	}
+
+	if err := c.Set(ctx, "a", []byte("1"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := c.Get(ctx, "a"); !ok || err != nil || string(v) != "1" {
+		t.Fatalf("Get(a) = %q, %v, %v", v, ok, err)
+	}
+	if keys := server.Keys(); !slices.Equal(keys, []string{"test:a"}) {
+		t.Errorf("stored keys = %v, want them prefixed", keys)
+	}
+
+	if err := c.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("Get(a) found a deleted key")
+	}
+
+	for want := int64(1); want <= 2; want++ {
+		if n, err := c.Incr(ctx, "counter"); n != want || err != nil {
+			t.Errorf("Incr = %d, %v; want %d", n, err, want)
+		}
+	}
+}
+
+func TestRedisExpiry(t *testing.T) {
+	c, _ := newRedis(t)
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "a", []byte("1"), 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("Get returned an expired entry")
+	}
+}
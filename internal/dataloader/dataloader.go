@@ -0,0 +1,71 @@
+// Package dataloader batches lookups made by independent resolvers into a
+// single fetch, avoiding one query per item when resolving nested fields.
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+// Loader collects keys passed to Load and fetches them together the first
+// time any of the returned thunks is called. Results are cached for the
+// Loader's lifetime, which should be a single request.
+type Loader[K comparable, V any] struct {
+	fetch func(context.Context, []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]struct{}
+	values  map[K]V
+	errs    map[K]error
+}
+
+// New returns a Loader backed by fetch. Keys missing from the map fetch
+// returns resolve to the zero value.
+func New[K comparable, V any](fetch func(context.Context, []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:  fetch,
+		queued: map[K]struct{}{},
+		values: map[K]V{},
+		errs:   map[K]error{},
+	}
+}
+
+// Load queues key and returns a thunk resolving it. Call every Load of a
+// batch before calling any thunk.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	if _, ok := l.values[key]; !ok {
+		if _, ok := l.queued[key]; !ok {
+			l.queued[key] = struct{}{}
+			l.pending = append(l.pending, key)
+		}
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if _, ok := l.queued[key]; ok {
+			l.flush(ctx)
+		}
+
+		return l.values[key], l.errs[key]
+	}
+}
+
+func (l *Loader[K, V]) flush(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+
+	results, err := l.fetch(ctx, keys)
+	for _, k := range keys {
+		delete(l.queued, k)
+		if err != nil {
+			l.errs[k] = err
+			continue
+		}
+		l.values[k] = results[k]
+	}
+}
@@ -1,28 +1,281 @@
 package env
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"reflect"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	urlType      = reflect.TypeOf(url.URL{})
 )
 
-func GetString(key string, fallback string) string {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
-		return fallback
+// Load populates dst, a pointer to a struct, from the `default` and `env`
+// struct tags. Values are resolved in order of increasing precedence:
+// the default, the optional config file at path, then the environment.
+// A variable named KEY can also be read from the file named by KEY_FILE,
+// which is how Docker secrets are mounted.
+//
+// Every parse and `validate` failure is reported in the returned error
+// rather than only the first one.
+func Load(dst any, path string) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return errors.New("env: destination must be a pointer to a struct")
+	}
+
+	values := map[string]string{}
+	if path != "" {
+		fileValues, err := readFile(path)
+		if err != nil {
+			return err
+		}
+		values = fileValues
+	}
+
+	var errs []error
+	failed := map[string]bool{}
+	secrets := map[string]bool{}
+	walk(v.Elem(), func(field reflect.Value, sf reflect.StructField, key string) {
+		secrets[key] = isSecret(sf)
+
+		raw, ok, err := lookup(key, values)
+		if err != nil {
+			errs = append(errs, err)
+			failed[key] = true
+			return
+		}
+		if !ok {
+			raw, ok = sf.Tag.Lookup("default")
+		}
+		if !ok {
+			return
+		}
+
+		if err := set(field, raw); err != nil {
+			// Parse errors quote the value, so a secret's is left out.
+			if secrets[key] {
+				err = errors.New("invalid value")
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			failed[key] = true
+		}
+	})
+
+	errs = append(errs, validate(dst, failed, secrets)...)
+
+	return errors.Join(errs...)
+}
+
+// Dump returns the effective configuration keyed by variable name. Fields
+// tagged `secret:"true"` and passwords embedded in URLs are redacted.
+func Dump(src any) map[string]string {
+	v := reflect.ValueOf(src)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	out := map[string]string{}
+	walk(v, func(field reflect.Value, sf reflect.StructField, key string) {
+		out[key] = redact(format(field), isSecret(sf))
+	})
+
+	return out
+}
+
+// walk calls fn for every field carrying an `env` tag, descending into
+// nested structs.
+func walk(v reflect.Value, fn func(reflect.Value, reflect.StructField, string)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		field := v.Field(i)
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		key, ok := sf.Tag.Lookup("env")
+		if !ok {
+			if sf.Type.Kind() == reflect.Struct && sf.Type != urlType {
+				walk(field, fn)
+			}
+			continue
+		}
+
+		fn(field, sf, key)
+	}
+}
+
+func lookup(key string, fileValues map[string]string) (string, bool, error) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value, true, nil
+	}
+
+	if path, ok := os.LookupEnv(key + "_FILE"); ok && path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("%s_FILE: %w", key, err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), true, nil
+	}
+
+	value, ok := fileValues[key]
+	return value, ok, nil
+}
+
+func set(field reflect.Value, raw string) error {
+	switch {
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+
+	case field.Type() == urlType:
+		u, err := url.Parse(raw)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported list type %s", field.Type())
+		}
+
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+
+	return nil
+}
+
+func format(field reflect.Value) string {
+	switch {
+	case field.Type() == durationType:
+		return time.Duration(field.Int()).String()
+	case field.Type() == urlType:
+		u := field.Interface().(url.URL)
+		return u.String()
+	case field.Kind() == reflect.Slice:
+		return strings.Join(field.Interface().([]string), ",")
+	}
+
+	return fmt.Sprint(field.Interface())
+}
+
+func isSecret(sf reflect.StructField) bool {
+	return sf.Tag.Get("secret") == "true"
+}
+
+// redact hides a non-empty secret value entirely and the password of a URL.
+func redact(value string, secret bool) string {
+	if value != "" && secret {
+		return "[REDACTED]"
 	}
-	return value
+	return redactURL(value)
 }
 
-func GetInt(key string, fallback int) int {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
-		return fallback
+func redactURL(value string) string {
+	if !strings.Contains(value, "://") {
+		return value
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "REDACTED")
 	}
 
-	intValue, err := strconv.Atoi(value)
-	if err != nil {
-		return fallback
+	return u.String()
+}
+
+// validate runs the `validate` tags, skipping keys that already failed to
+// parse so each variable is reported once. Values are redacted in errors as
+// they are by Dump.
+func validate(dst any, skip, secrets map[string]bool) []error {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(sf reflect.StructField) string {
+		return sf.Tag.Get("env")
+	})
+
+	err := v.Struct(dst)
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		if err != nil {
+			return []error{err}
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, fe := range verrs {
+		if skip[fe.Field()] {
+			continue
+		}
+
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule += "=" + fe.Param()
+		}
+		errs = append(errs, fmt.Errorf("%s: value %q does not satisfy %q", fe.Field(), redact(fmt.Sprint(fe.Value()), secrets[fe.Field()]), rule))
 	}
 
-	return intValue
+	return errs
 }
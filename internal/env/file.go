@@ -0,0 +1,67 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// readFile loads a YAML or TOML config file and flattens it into variable
+// names, so that
+//
+//	db:
+//	  max_open_conns: 30
+//
+// overlays DB_MAX_OPEN_CONNS.
+func readFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &doc)
+	case ".toml":
+		err = toml.Unmarshal(b, &doc)
+	default:
+		return nil, fmt.Errorf("env: unsupported config file extension %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("env: parsing %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	flatten("", doc, values)
+
+	return values, nil
+}
+
+func flatten(prefix string, doc map[string]any, values map[string]string) {
+	for k, v := range doc {
+		key := strings.ToUpper(k)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		switch v := v.(type) {
+		case map[string]any:
+			flatten(key, v, values)
+		case []any:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(items, ",")
+		case nil:
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Package events dispatches domain events recorded in a transactional
+// outbox to in-process subscribers. An event is written in the same
+// transaction as the change it describes, so it exists if and only if the
+// change was committed, and it is retried until every subscriber has
+// handled it. Delivery is at least once: subscribers must be idempotent.
+//
+// Each event is claimed by a single replica, so the bus is only for effects
+// that must happen once; today its one subscriber queues webhook
+// deliveries. Caches that every replica keeps are invalidated from the
+// NOTIFY sent with each change instead, by package stream.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// claimBatchSize bounds how many events are handled per poll.
+	claimBatchSize = 50
+	// maxBackoff caps the delay between two attempts at an event.
+	maxBackoff = time.Hour
+	// pruneInterval is how often processed events are removed.
+	pruneInterval = time.Hour
+)
+
+// Event is a domain event read back from the outbox. Attempt counts the
+// current attempt at dispatching it.
+type Event struct {
+	ID           int64
+	Type         string
+	ResourceType string
+	ResourceID   string
+	Payload      json.RawMessage
+	Actor        *string
+	OccurredAt   time.Time
+	Attempt      int
+}
+
+// Handler processes an event. Returning an error has the event dispatched
+// again later, to every subscriber.
+type Handler func(ctx context.Context, e Event) error
+
+// Source is the outbox events are claimed from.
+type Source interface {
+	// Claim takes up to limit due events and hides them from other
+	// pollers for lease.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]Event, error)
+	// Complete marks an event as processed.
+	Complete(ctx context.Context, id int64) error
+	// Retry schedules an event to be dispatched again at the given time.
+	Retry(ctx context.Context, id int64, at time.Time, cause string) error
+	// Discard gives up on an event, keeping it for inspection.
+	Discard(ctx context.Context, id int64, cause string) error
+	// Prune removes events processed before the given time.
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Config tunes a Bus.
+type Config struct {
+	// PollInterval is how often the outbox is checked for new events.
+	PollInterval time.Duration
+	// MaxAttempts is how often an event is dispatched before it is
+	// discarded.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It doubles with every
+	// further attempt.
+	Backoff time.Duration
+	// Retention is how long processed events are kept.
+	Retention time.Duration
+}
+
+type subscriber struct {
+	name   string
+	types  []string
+	handle Handler
+}
+
+// Bus polls a Source and fans events out to its subscribers.
+type Bus struct {
+	source Source
+	logger *zap.SugaredLogger
+	config Config
+
+	mu          sync.RWMutex
+	subscribers []subscriber
+}
+
+func NewBus(source Source, logger *zap.SugaredLogger, config Config) *Bus {
+	return &Bus{source: source, logger: logger, config: config}
+}
+
+// Subscribe registers handle under name for events of the given types, or
+// for every event when no type is given.
+func (b *Bus) Subscribe(name string, handle Handler, types ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers = append(b.subscribers, subscriber{name: name, types: types, handle: handle})
+}
+
+// Run dispatches events until ctx is cancelled.
+func (b *Bus) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.config.PollInterval)
+	defer ticker.Stop()
+
+	lastPrune := time.Time{}
+
+	for {
+		// Keep draining while there is a full backlog.
+		for b.poll(ctx) == claimBatchSize {
+		}
+
+		if time.Since(lastPrune) >= pruneInterval {
+			b.prune(ctx)
+			lastPrune = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll dispatches one batch of events, in order, and returns its size.
+func (b *Bus) poll(ctx context.Context) int {
+	// Subscribers get the lease to handle the whole batch.
+	lease := max(time.Minute, 10*b.config.PollInterval)
+
+	events, err := b.source.Claim(ctx, claimBatchSize, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			b.logger.Errorw("claiming outbox events", "error", err.Error())
+		}
+		return 0
+	}
+
+	for _, e := range events {
+		err := b.dispatch(ctx, e)
+
+		// Record the outcome even if ctx was cancelled meanwhile, so that
+		// a handled event is not dispatched again on the next start.
+		recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+
+		switch {
+		case err == nil:
+			err = b.source.Complete(recordCtx, e.ID)
+
+		case e.Attempt >= b.config.MaxAttempts:
+			b.logger.Errorw("discarding outbox event", "id", e.ID, "type", e.Type, "attempt", e.Attempt, "error", err.Error())
+			err = b.source.Discard(recordCtx, e.ID, err.Error())
+
+		default:
+			b.logger.Warnw("outbox event failed", "id", e.ID, "type", e.Type, "attempt", e.Attempt, "error", err.Error())
+			err = b.source.Retry(recordCtx, e.ID, time.Now().Add(b.backoff(e.Attempt)), err.Error())
+		}
+		cancel()
+
+		if err != nil {
+			b.logger.Errorw("recording outbox event outcome", "id", e.ID, "error", err.Error())
+		}
+	}
+
+	return len(events)
+}
+
+// dispatch hands e to every interested subscriber, even if one fails, and
+// joins their errors.
+func (b *Bus) dispatch(ctx context.Context, e Event) error {
+	b.mu.RLock()
+	subscribers := slices.Clone(b.subscribers)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subscribers {
+		if len(s.types) > 0 && !slices.Contains(s.types, e.Type) {
+			continue
+		}
+
+		if err := s.handle(ctx, e); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (b *Bus) prune(ctx context.Context) {
+	removed, err := b.source.Prune(ctx, time.Now().Add(-b.config.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			b.logger.Errorw("pruning outbox events", "error", err.Error())
+		}
+		return
+	}
+
+	if removed > 0 {
+		b.logger.Infow("pruned outbox events", "count", removed)
+	}
+}
+
+// backoff returns the delay after the given attempt: Backoff, then twice
+// that, and so on up to maxBackoff.
+func (b *Bus) backoff(attempt int) time.Duration {
+	delay := b.config.Backoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
@@ -0,0 +1,215 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// memorySource is an outbox in memory. Claim hands out pending events in
+// order; outcomes are recorded for the test to inspect.
+type memorySource struct {
+	mu        sync.Mutex
+	pending   []Event
+	claims    int
+	completed []int64
+	retried   map[int64]time.Time
+	discarded map[int64]string
+}
+
+func newMemorySource(events ...Event) *memorySource {
+	return &memorySource{pending: events, retried: map[int64]time.Time{}, discarded: map[int64]string{}}
+}
+
+func (s *memorySource) Claim(ctx context.Context, limit int, lease time.Duration) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.claims++
+	n := min(limit, len(s.pending))
+	claimed := s.pending[:n]
+	s.pending = s.pending[n:]
+	return claimed, nil
+}
+
+func (s *memorySource) Complete(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completed = append(s.completed, id)
+	return nil
+}
+
+func (s *memorySource) Retry(ctx context.Context, id int64, at time.Time, cause string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retried[id] = at
+	return nil
+}
+
+func (s *memorySource) Discard(ctx context.Context, id int64, cause string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.discarded[id] = cause
+	return nil
+}
+
+func (s *memorySource) Prune(ctx context.Context, before time.Time) (int64, error) { return 0, nil }
+
+func (s *memorySource) completedCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.completed)
+}
+
+func newTestBus(source Source) *Bus {
+	return NewBus(source, zap.NewNop().Sugar(), Config{
+		PollInterval: time.Hour,
+		MaxAttempts:  3,
+		Backoff:      time.Minute,
+		Retention:    time.Hour,
+	})
+}
+
+func failing(err error) Handler {
+	return func(context.Context, Event) error { return err }
+}
+
+func TestPollCompletesHandledEvents(t *testing.T) {
+	source := newMemorySource(Event{ID: 1, Type: "experience.created", Attempt: 1}, Event{ID: 2, Type: "experience.deleted", Attempt: 1})
+	bus := newTestBus(source)
+
+	var handled []int64
+	bus.Subscribe("created", func(ctx context.Context, e Event) error {
+		handled = append(handled, e.ID)
+		return nil
+	}, "experience.created")
+
+	if n := bus.poll(context.Background()); n != 2 {
+		t.Fatalf("poll() = %d, want 2", n)
+	}
+	if len(handled) != 1 || handled[0] != 1 {
+		t.Errorf("subscriber handled %v, want only the event of its type", handled)
+	}
+	if len(source.completed) != 2 {
+		t.Errorf("completed %v, want both events", source.completed)
+	}
+}
+
+func TestPollRetriesWithBackoff(t *testing.T) {
+	source := newMemorySource(Event{ID: 1, Attempt: 2})
+	bus := newTestBus(source)
+	bus.Subscribe("webhooks", failing(errors.New("connection refused")))
+
+	before := time.Now()
+	bus.poll(context.Background())
+
+	at, ok := source.retried[1]
+	if !ok {
+		t.Fatal("the failed event was not retried")
+	}
+	if delay := at.Sub(before); delay < 2*time.Minute || delay > 2*time.Minute+time.Second {
+		t.Errorf("retried after %s, want 2m0s for the second attempt", delay)
+	}
+	if len(source.completed) != 0 || len(source.discarded) != 0 {
+		t.Errorf("completed %v, discarded %v, want neither", source.completed, source.discarded)
+	}
+}
+
+func TestPollDiscardsAfterMaxAttempts(t *testing.T) {
+	source := newMemorySource(Event{ID: 1, Attempt: 3})
+	bus := newTestBus(source)
+	bus.Subscribe("webhooks", failing(errors.New("connection refused")))
+
+	bus.poll(context.Background())
+
+	if cause, ok := source.discarded[1]; !ok || !strings.Contains(cause, "connection refused") {
+		t.Errorf("discarded = %v, want the event with its cause", source.discarded)
+	}
+	if len(source.retried) != 0 {
+		t.Errorf("retried %v after the last attempt", source.retried)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		backoff time.Duration
+		attempt int
+		want    time.Duration
+	}{
+		{time.Minute, 1, time.Minute},
+		{time.Minute, 2, 2 * time.Minute},
+		{time.Minute, 4, 8 * time.Minute},
+		{time.Minute, 100, maxBackoff},
+		{40 * time.Minute, 2, maxBackoff},
+	}
+
+	for _, tt := range tests {
+		bus := NewBus(nil, nil, Config{Backoff: tt.backoff})
+		if got := bus.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) with Backoff %s = %s, want %s", tt.attempt, tt.backoff, got, tt.want)
+		}
+	}
+}
+
+func TestDispatchJoinsSubscriberErrors(t *testing.T) {
+	bus := newTestBus(newMemorySource())
+
+	errWebhooks := errors.New("queue full")
+	errSearch := errors.New("index unavailable")
+	called := false
+	bus.Subscribe("webhooks", failing(errWebhooks))
+	bus.Subscribe("audit", func(context.Context, Event) error {
+		called = true
+		return nil
+	})
+	bus.Subscribe("search", failing(errSearch))
+
+	err := bus.dispatch(context.Background(), Event{ID: 1})
+
+	if !errors.Is(err, errWebhooks) || !errors.Is(err, errSearch) {
+		t.Errorf("dispatch() = %v, want both subscriber errors", err)
+	}
+	if !strings.Contains(err.Error(), "webhooks: ") || !strings.Contains(err.Error(), "search: ") {
+		t.Errorf("dispatch() = %q, want errors named after their subscriber", err)
+	}
+	if !called {
+		t.Error("a failing subscriber kept the others from running")
+	}
+}
+
+func TestRunDrainsFullBatches(t *testing.T) {
+	var backlog []Event
+	for id := range int64(2*claimBatchSize + 10) {
+		backlog = append(backlog, Event{ID: id + 1, Attempt: 1})
+	}
+	source := newMemorySource(backlog...)
+	bus := newTestBus(source)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		bus.Run(ctx)
+		close(done)
+	}()
+
+	// The poll interval is an hour, so the backlog only empties in time if
+	// Run keeps claiming while batches come back full.
+	deadline := time.Now().Add(time.Second)
+	for source.completedCount() < len(backlog) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if n := source.completedCount(); n != len(backlog) {
+		t.Fatalf("completed %d of %d events", n, len(backlog))
+	}
+	if source.claims != 3 {
+		t.Errorf("claimed %d times, want 3", source.claims)
+	}
+}
@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// Negotiator picks the best supported locale for a request.
+type Negotiator struct {
+	defaultLocale string
+	supported     []string
+	matcher       language.Matcher
+}
+
+// NewNegotiator builds a negotiator over the supported locales. The default
+// locale is the language content is authored in and is always supported.
+func NewNegotiator(defaultLocale string, supported []string) (*Negotiator, error) {
+	locales := []string{defaultLocale}
+	for _, locale := range supported {
+		if locale != defaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+
+	tags := make([]language.Tag, len(locales))
+	for i, locale := range locales {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return nil, fmt.Errorf("i18n: invalid locale %q: %w", locale, err)
+		}
+		tags[i] = tag
+	}
+
+	return &Negotiator{
+		defaultLocale: defaultLocale,
+		supported:     locales,
+		matcher:       language.NewMatcher(tags),
+	}, nil
+}
+
+// Default returns the locale content is authored in.
+func (n *Negotiator) Default() string {
+	return n.defaultLocale
+}
+
+// Supported reports whether locale is one of the configured locales.
+func (n *Negotiator) Supported(locale string) bool {
+	for _, l := range n.supported {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate returns the locale requested explicitly (e.g. via ?lang=) when
+// it is supported, otherwise the best match for the Accept-Language header,
+// falling back to the default locale.
+func (n *Negotiator) Negotiate(explicit, acceptLanguage string) string {
+	if explicit != "" {
+		_, index, confidence := n.matcher.Match(language.Make(explicit))
+		if confidence != language.No {
+			return n.supported[index]
+		}
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return n.defaultLocale
+	}
+
+	_, index, confidence := n.matcher.Match(tags...)
+	if confidence == language.No {
+		return n.defaultLocale
+	}
+
+	return n.supported[index]
+}
@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// New builds a zap logger for the given level (debug, info, warn, error)
+// and format (json or console).
+func New(level, format string) (*zap.Logger, error) {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg zap.Config
+	switch format {
+	case "", "json":
+		cfg = zap.NewProductionConfig()
+	case "console":
+		cfg = zap.NewDevelopmentConfig()
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+
+	cfg.Level = zap.NewAtomicLevelAt(lvl)
+
+	return cfg.Build()
+}
@@ -0,0 +1,161 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+const usage = "usage: migrate up | down | status | redo | to <version>"
+
+// Migrator applies the embedded goose migrations. Every operation holds a
+// Postgres advisory lock so that concurrently starting replicas do not race.
+type Migrator struct {
+	provider *goose.Provider
+}
+
+func New(db *sql.DB, migrations fs.FS) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations,
+		goose.WithSessionLocker(locker),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{provider: provider}, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return m.provider.Up(ctx)
+}
+
+// Down rolls back the most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	return m.provider.Down(ctx)
+}
+
+// Redo rolls back the most recently applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) ([]*goose.MigrationResult, error) {
+	down, err := m.provider.Down(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	up, err := m.provider.UpByOne(ctx)
+	if err != nil {
+		return []*goose.MigrationResult{down}, err
+	}
+
+	return []*goose.MigrationResult{down, up}, nil
+}
+
+// To migrates up or down until the database is at version.
+func (m *Migrator) To(ctx context.Context, version int64) ([]*goose.MigrationResult, error) {
+	current, err := m.provider.GetDBVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case version > current:
+		return m.provider.UpTo(ctx, version)
+	case version < current:
+		return m.provider.DownTo(ctx, version)
+	}
+
+	return nil, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return m.provider.Status(ctx)
+}
+
+// Run executes a migrate subcommand such as "up" or "to 2" and reports the
+// outcome to out.
+func (m *Migrator) Run(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	var (
+		results []*goose.MigrationResult
+		err     error
+	)
+
+	switch args[0] {
+	case "up":
+		results, err = m.Up(ctx)
+
+	case "down":
+		var result *goose.MigrationResult
+		result, err = m.Down(ctx)
+		if result != nil {
+			results = append(results, result)
+		}
+
+	case "redo":
+		results, err = m.Redo(ctx)
+
+	case "to":
+		if len(args) != 2 {
+			return errors.New(usage)
+		}
+
+		version, perr := strconv.ParseInt(args[1], 10, 64)
+		if perr != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		results, err = m.To(ctx, version)
+
+	case "status":
+		return m.printStatus(ctx, out)
+
+	default:
+		return errors.New(usage)
+	}
+
+	for _, result := range results {
+		fmt.Fprintln(out, result)
+	}
+
+	if len(results) == 0 && err == nil {
+		fmt.Fprintln(out, "no migrations to run")
+	}
+
+	return err
+}
+
+func (m *Migrator) printStatus(ctx context.Context, out io.Writer) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tSTATE\tAPPLIED AT\tSOURCE")
+
+	for _, s := range statuses {
+		appliedAt := "-"
+		if !s.AppliedAt.IsZero() {
+			appliedAt = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", s.Source.Version, s.State, appliedAt, s.Source.Path)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+//go:embed docs.html
+var docsHTML string
+
+// swaggerUI holds the Swagger UI release the docs page is built from.
+// VERSION pins it; `make swagger-ui` vendors its assets next to it.
+//
+//go:embed swagger-ui
+var swaggerUI embed.FS
+
+var docsTemplate = template.Must(template.New("docs").Parse(docsHTML))
+
+// DocsHandler serves a Swagger UI page titled title that renders the
+// document at specURL. The vendored Swagger UI assets are inlined into the
+// page, so it needs nothing but the document; until they are vendored the
+// browser loads the pinned release from a CDN instead.
+func DocsHandler(title, specURL string) http.Handler {
+	data := struct {
+		Title, SpecURL, Version string
+		CSS                     template.CSS
+		JS                      template.JS
+	}{Title: title, SpecURL: specURL}
+
+	version, err := fs.ReadFile(swaggerUI, "swagger-ui/VERSION")
+	if err != nil {
+		panic(err)
+	}
+	data.Version = strings.TrimSpace(string(version))
+
+	css, cssErr := fs.ReadFile(swaggerUI, "swagger-ui/swagger-ui.css")
+	js, jsErr := fs.ReadFile(swaggerUI, "swagger-ui/swagger-ui-bundle.js")
+	if cssErr == nil && jsErr == nil {
+		data.CSS = template.CSS(css)
+		data.JS = template.JS(js)
+	}
+
+	var buf bytes.Buffer
+	if err := docsTemplate.Execute(&buf, data); err != nil {
+		panic(err)
+	}
+	page := buf.Bytes()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	})
+}
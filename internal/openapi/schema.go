@@ -0,0 +1,316 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// Generator derives schemas from Go types. Named struct types become
+// component schemas and are referenced, so each is described only once.
+type Generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+	enums   map[string][]string
+}
+
+func NewGenerator() *Generator {
+	return &Generator{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+		enums:   map[string][]string{},
+	}
+}
+
+// Enum documents a custom validator rule that accepts only values. Call it
+// before generating the schemas that use the rule.
+func (g *Generator) Enum(rule string, values []string) {
+	g.enums[rule] = values
+}
+
+// Schema returns the schema of the type of v, which is usually a zero value
+// such as store.Experience{} or []*store.Experience(nil).
+func (g *Generator) Schema(v any) *Schema {
+	return g.schema(reflect.TypeOf(v))
+}
+
+// Schemas returns the component schemas of every named type seen so far.
+func (g *Generator) Schemas() map[string]*Schema {
+	return g.schemas
+}
+
+func (g *Generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return g.ref(t)
+	default:
+		// Interfaces hold any JSON value.
+		return &Schema{}
+	}
+}
+
+func (g *Generator) ref(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = g.componentName(t)
+		g.names[t] = name
+
+		// Register before describing the fields so that recursive types
+		// refer to themselves instead of recursing forever.
+		s := &Schema{}
+		g.schemas[name] = s
+		*s = *g.object(t)
+	}
+
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+var typeArgName = regexp.MustCompile(`[\w./-]*\.`)
+
+// componentName names the component of t after the type, dropping package
+// paths from type arguments so that PaginatedResponse[*store.Experience]
+// becomes PaginatedResponseExperience.
+func (g *Generator) componentName(t reflect.Type) string {
+	name := t.Name()
+	if base, args, ok := strings.Cut(name, "["); ok {
+		name = base
+		for _, arg := range strings.Split(typeArgName.ReplaceAllString(args, ""), ",") {
+			name += exported(strings.Map(func(r rune) rune {
+				if unicode.IsLetter(r) || unicode.IsDigit(r) {
+					return r
+				}
+				return -1
+			}, arg))
+		}
+	}
+	name = exported(name)
+
+	if _, taken := g.schemas[name]; taken {
+		name = exported(path.Base(t.PkgPath())) + name
+	}
+
+	return name
+}
+
+func exported(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func (g *Generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.fields(t, s)
+	return s
+}
+
+// fields adds the properties of struct t to s the way encoding/json
+// marshals them, flattening embedded structs.
+func (g *Generator) fields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.fields(ft, s)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := g.schema(f.Type)
+		if f.Type.Kind() == reflect.Pointer {
+			if typ, ok := fs.Type.(string); ok {
+				fs.Type = []string{typ, "null"}
+			}
+		}
+
+		if applyRules(fs, f.Type, f.Tag.Get("validate"), g.enums) {
+			s.Required = append(s.Required, name)
+		}
+
+		s.Properties[name] = fs
+	}
+}
+
+// applyRules narrows s by the validator rules in tag and reports whether
+// the field is required. Rules after dive apply to the items of s; enums
+// holds the values of custom enumeration rules.
+func applyRules(s *Schema, t reflect.Type, tag string, enums map[string][]string) (required bool) {
+	if tag == "" {
+		return false
+	}
+
+	target := s
+	for _, rule := range strings.Split(tag, ",") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "dive":
+			if target.Items == nil || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+				return required
+			}
+			target, t = target.Items, t.Elem()
+
+		case "required":
+			required = required || target == s
+
+		case "min", "max", "len":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			limitSize(target, t, name, n)
+
+		case "gt", "gte", "lt", "lte":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil || !isNumber(t) {
+				continue
+			}
+			switch name {
+			case "gt":
+				target.ExclusiveMinimum = &n
+			case "gte":
+				target.Minimum = &n
+			case "lt":
+				target.ExclusiveMaximum = &n
+			case "lte":
+				target.Maximum = &n
+			}
+
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				if n, err := strconv.ParseFloat(v, 64); err == nil && isNumber(t) {
+					target.Enum = append(target.Enum, n)
+				} else {
+					target.Enum = append(target.Enum, v)
+				}
+			}
+
+		case "unique":
+			target.UniqueItems = true
+
+		case "url", "http_url", "uri":
+			target.Format = "uri"
+
+		case "email", "uuid", "hostname", "ipv4", "ipv6":
+			target.Format = name
+
+		case "startswith":
+			target.Pattern = "^" + regexp.QuoteMeta(param)
+
+		case "endswith":
+			target.Pattern = regexp.QuoteMeta(param) + "$"
+
+		case "bcp47_language_tag":
+			target.Description = "A BCP 47 language tag."
+
+		default:
+			for _, v := range enums[name] {
+				target.Enum = append(target.Enum, v)
+			}
+		}
+	}
+
+	return required
+}
+
+func limitSize(s *Schema, t reflect.Type, rule string, n int) {
+	switch {
+	case t.Kind() == reflect.String:
+		if rule != "max" {
+			s.MinLength = &n
+		}
+		if rule != "min" {
+			s.MaxLength = &n
+		}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map:
+		if rule != "max" {
+			s.MinItems = &n
+		}
+		if rule != "min" {
+			s.MaxItems = &n
+		}
+	case isNumber(t):
+		v := float64(n)
+		if rule != "max" {
+			s.Minimum = &v
+		}
+		if rule != "min" {
+			s.Maximum = &v
+		}
+	}
+}
+
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package openapi
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func schemaJSON(t *testing.T, s *Schema) string {
+	t.Helper()
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestApplyRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		tag      string
+		want     string
+		required bool
+	}{
+		{"required string", "", "required,min=1,max=255", `{"type":"string","minLength":1,"maxLength":255}`, true},
+		{"exact length", "", "len=2", `{"type":"string","minLength":2,"maxLength":2}`, false},
+		{"integer bounds", 0, "min=1,max=100", `{"type":"integer","format":"int64","minimum":1,"maximum":100}`, false},
+		{"exclusive bounds", 0.0, "gt=0,lt=1", `{"type":"number","format":"double","exclusiveMinimum":0,"exclusiveMaximum":1}`, false},
+		{"inclusive bounds", int32(0), "gte=-5,lte=5", `{"type":"integer","format":"int32","minimum":-5,"maximum":5}`, false},
+		{"bounds on a string are ignored", "", "gt=0", `{"type":"string"}`, false},
+		{"string enum", "", "oneof=public unlisted private", `{"type":"string","enum":["public","unlisted","private"]}`, false},
+		{"number enum", 0, "oneof=1 2", `{"type":"integer","format":"int64","enum":[1,2]}`, false},
+		{"array size", []string{}, "min=1,max=3,unique", `{"type":"array","items":{"type":"string"},"minItems":1,"maxItems":3,"uniqueItems":true}`, false},
+		{"dive", []string{}, "required,dive,required,max=10", `{"type":"array","items":{"type":"string","maxLength":10}}`, true},
+		{"dive enum", []string{}, "dive,oneof=a b", `{"type":"array","items":{"type":"string","enum":["a","b"]}}`, false},
+		{"dive into a non-slice", "", "dive,max=1", `{"type":"string"}`, false},
+		{"url", "", "omitempty,url", `{"type":"string","format":"uri"}`, false},
+		{"email", "", "email", `{"type":"string","format":"email"}`, false},
+		{"prefix", "", "startswith=v1.", `{"type":"string","pattern":"^v1\\."}`, false},
+		{"suffix", "", "endswith=.pdf", `{"type":"string","pattern":"\\.pdf$"}`, false},
+		{"language tag", "", "bcp47_language_tag", `{"type":"string","description":"A BCP 47 language tag."}`, false},
+		{"malformed parameter", "", "max=many", `{"type":"string"}`, false},
+		{"custom enum", []string{}, "dive,color", `{"type":"array","items":{"type":"string","enum":["red","green"]}}`, false},
+		{"unknown rule", "", "shape", `{"type":"string"}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ := reflect.TypeOf(tt.value)
+			s := NewGenerator().schema(typ)
+
+			required := applyRules(s, typ, tt.tag, map[string][]string{"color": {"red", "green"}})
+
+			if got := schemaJSON(t, s); got != tt.want {
+				t.Errorf("schema = %s, want %s", got, tt.want)
+			}
+			if required != tt.required {
+				t.Errorf("required = %v, want %v", required, tt.required)
+			}
+		})
+	}
+}
+
+type testPage[T any] struct {
+	Data []T `json:"data"`
+}
+
+type testBase struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testNode struct {
+	testBase
+	Title    string          `json:"title" validate:"required,max=80"`
+	Note     *string         `json:"note"`
+	Children []*testNode     `json:"children,omitempty"`
+	Extra    json.RawMessage `json:"extra"`
+	Secret   string          `json:"-"`
+	internal string
+}
+
+func TestGeneratorSchema(t *testing.T) {
+	g := NewGenerator()
+
+	if got, want := schemaJSON(t, g.Schema(testPage[*testNode]{})), `{"$ref":"#/components/schemas/TestPageTestNode"}`; got != want {
+		t.Errorf("Schema() = %s, want %s", got, want)
+	}
+
+	schemas := g.Schemas()
+	node, ok := schemas["TestNode"]
+	if !ok {
+		t.Fatalf("components = %v, want TestNode", slices.Sorted(maps.Keys(schemas)))
+	}
+
+	wantProperties := map[string]string{
+		"id":         `{"type":"integer","format":"int64"}`,
+		"created_at": `{"type":"string","format":"date-time"}`,
+		"title":      `{"type":"string","maxLength":80}`,
+		"note":       `{"type":["string","null"]}`,
+		"children":   `{"type":"array","items":{"$ref":"#/components/schemas/TestNode"}}`,
+		"extra":      `{}`,
+	}
+	if len(node.Properties) != len(wantProperties) {
+		t.Errorf("properties = %v, want %v", slices.Sorted(maps.Keys(node.Properties)), slices.Sorted(maps.Keys(wantProperties)))
+	}
+	for name, want := range wantProperties {
+		if got := schemaJSON(t, node.Properties[name]); got != want {
+			t.Errorf("property %s = %s, want %s", name, got, want)
+		}
+	}
+	if !slices.Equal(node.Required, []string{"title"}) {
+		t.Errorf("required = %v, want [title]", node.Required)
+	}
+}
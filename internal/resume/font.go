@@ -0,0 +1,49 @@
+package resume
+
+import (
+	"embed"
+	"os"
+)
+
+//go:embed fonts/*.ttf
+var fonts embed.FS
+
+// fontFamily is the name the font is registered under in every document.
+const fontFamily = "body"
+
+// Font is the TrueType family résumés are set in. Text is embedded as
+// UTF-8, so any script the font has glyphs for is rendered.
+type Font struct {
+	Regular []byte
+	Bold    []byte
+	Italic  []byte
+}
+
+// DefaultFont returns the bundled DejaVu Sans Condensed, which covers
+// Latin, Greek and Cyrillic but not Khmer.
+func DefaultFont() *Font {
+	read := func(name string) []byte {
+		b, err := fonts.ReadFile("fonts/" + name)
+		if err != nil {
+			panic(err)
+		}
+		return b
+	}
+
+	return &Font{
+		Regular: read("DejaVuSansCondensed.ttf"),
+		Bold:    read("DejaVuSansCondensed-Bold.ttf"),
+		Italic:  read("DejaVuSansCondensed-Oblique.ttf"),
+	}
+}
+
+// LoadFont reads a TrueType file to use for every style, such as Noto Sans
+// Khmer for content in Khmer.
+func LoadFont(path string) (*Font, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Font{Regular: b, Bold: b, Italic: b}, nil
+}
@@ -0,0 +1,184 @@
+package resume
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// Template controls the layout of a rendered résumé.
+type Template struct {
+	TitleSize    float64
+	HeadingSize  float64
+	BodySize     float64
+	LineHeight   float64
+	Margin       float64
+	EntrySpacing float64
+	Accent       [3]int
+}
+
+var templates = map[string]Template{
+	"classic": {
+		TitleSize:    22,
+		HeadingSize:  13,
+		BodySize:     11,
+		LineHeight:   5.5,
+		Margin:       20,
+		EntrySpacing: 6,
+		Accent:       [3]int{31, 58, 96},
+	},
+	"compact": {
+		TitleSize:    16,
+		HeadingSize:  10.5,
+		BodySize:     9,
+		LineHeight:   4.2,
+		Margin:       12,
+		EntrySpacing: 3,
+		Accent:       [3]int{60, 60, 60},
+	},
+}
+
+const DefaultTemplate = "classic"
+
+// Templates returns the names of the available templates.
+func Templates() []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Document is the content rendered into a résumé. Skills are listed by
+// category, in the order given.
+type Document struct {
+	Name        string
+	Experiences []*store.Experience
+	Skills      []*store.Skill
+}
+
+// RenderPDF writes doc as a paginated PDF using the named template, set in
+// font.
+func RenderPDF(w io.Writer, doc Document, template string, font *Font) error {
+	t, ok := templates[template]
+	if !ok {
+		return fmt.Errorf("unknown template %q", template)
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(t.Margin, t.Margin, t.Margin)
+	pdf.SetAutoPageBreak(true, t.Margin)
+	pdf.AliasNbPages("")
+
+	pdf.AddUTF8FontFromBytes(fontFamily, "", font.Regular)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", font.Bold)
+	pdf.AddUTF8FontFromBytes(fontFamily, "I", font.Italic)
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-t.Margin + 4)
+		pdf.SetFont(fontFamily, "I", t.BodySize-2)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.CellFormat(0, 4, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+
+	title := doc.Name
+	if title == "" {
+		title = "Résumé"
+	}
+	pdf.SetFont(fontFamily, "B", t.TitleSize)
+	pdf.SetTextColor(t.Accent[0], t.Accent[1], t.Accent[2])
+	pdf.CellFormat(0, t.TitleSize/2, title, "", 1, "L", false, 0, "")
+	pdf.Ln(t.EntrySpacing / 2)
+
+	heading(pdf, t, "Experience")
+
+	_, pageHeight := pdf.GetPageSize()
+
+	for _, e := range doc.Experiences {
+		// Keep an entry's title block together with at least one highlight.
+		if pdf.GetY()+3*t.LineHeight > pageHeight-t.Margin {
+			pdf.AddPage()
+		}
+
+		pdf.SetFont(fontFamily, "B", t.BodySize+1)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(0, t.LineHeight+1, e.Title, "", 1, "L", false, 0, "")
+
+		pdf.SetFont(fontFamily, "I", t.BodySize)
+		pdf.SetTextColor(80, 80, 80)
+		pdf.CellFormat(0, t.LineHeight, fmt.Sprintf("%s  |  %s", e.Company, period(e)), "", 1, "L", false, 0, "")
+
+		pdf.SetFont(fontFamily, "", t.BodySize)
+		pdf.SetTextColor(0, 0, 0)
+		for _, line := range e.Description {
+			pdf.SetX(t.Margin + 4)
+			pdf.MultiCell(0, t.LineHeight, "- "+line, "", "L", false)
+		}
+
+		pdf.Ln(t.EntrySpacing)
+	}
+
+	if len(doc.Skills) > 0 {
+		skills(pdf, t, doc.Skills)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+
+	return pdf.Output(w)
+}
+
+func heading(pdf *fpdf.Fpdf, t Template, text string) {
+	pdf.SetFont(fontFamily, "B", t.HeadingSize)
+	pdf.SetTextColor(t.Accent[0], t.Accent[1], t.Accent[2])
+	pdf.CellFormat(0, t.HeadingSize/2, strings.ToUpper(text), "B", 1, "L", false, 0, "")
+	pdf.Ln(t.EntrySpacing / 2)
+}
+
+// skills writes one line per category, uncategorised skills last.
+func skills(pdf *fpdf.Fpdf, t Template, list []*store.Skill) {
+	var categories []string
+	byCategory := map[string][]string{}
+	for _, s := range list {
+		category := "Other"
+		if s.Category != nil && *s.Category != "" {
+			category = *s.Category
+		}
+		if _, ok := byCategory[category]; !ok {
+			categories = append(categories, category)
+		}
+		byCategory[category] = append(byCategory[category], s.Name)
+	}
+	if i := slices.Index(categories, "Other"); i >= 0 {
+		categories = append(slices.Delete(categories, i, i+1), "Other")
+	}
+
+	heading(pdf, t, "Skills")
+
+	for _, category := range categories {
+		pdf.SetFont(fontFamily, "B", t.BodySize)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.Write(t.LineHeight, category+": ")
+
+		pdf.SetFont(fontFamily, "", t.BodySize)
+		pdf.Write(t.LineHeight, strings.Join(byCategory[category], ", "))
+		pdf.Ln(t.LineHeight)
+	}
+}
+
+func period(e *store.Experience) string {
+	if e.EndDate == "" {
+		return e.StartDate
+	}
+	return e.StartDate + " - " + e.EndDate
+}
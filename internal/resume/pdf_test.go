@@ -0,0 +1,52 @@
+package resume
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+func TestRenderPDF(t *testing.T) {
+	language := "Languages"
+	doc := Document{
+		Name: "Sokha Chan",
+		Experiences: []*store.Experience{{
+			Title:       "Ingénieure logicielle",
+			Company:     "Société Générale — Phnom Penh",
+			StartDate:   "2020-01",
+			EndDate:     "Present",
+			Description: []string{"Réduit la latence p99 de 900 ms à 180 ms", "Разработала API на Go"},
+		}},
+		Skills: []*store.Skill{
+			{ID: 1, Name: "Go", Category: &language},
+			{ID: 2, Name: "Kubernetes"},
+			{ID: 3, Name: "ភាសាខ្មែរ", Category: &language},
+		},
+	}
+
+	for _, template := range Templates() {
+		t.Run(template, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := RenderPDF(&buf, doc, template, DefaultFont()); err != nil {
+				t.Fatal(err)
+			}
+
+			out := buf.Bytes()
+			if !bytes.HasPrefix(out, []byte("%PDF-")) {
+				t.Fatalf("output does not start with a PDF header: %q", out[:min(len(out), 16)])
+			}
+			// Text set in an embedded TrueType font is written as glyph IDs
+			// with a ToUnicode map, rather than in a core font's encoding.
+			if !bytes.Contains(out, []byte("/FontFile2")) || !bytes.Contains(out, []byte("/ToUnicode")) {
+				t.Error("the font is not embedded as a UTF-8 TrueType font")
+			}
+		})
+	}
+}
+
+func TestRenderPDFUnknownTemplate(t *testing.T) {
+	if err := RenderPDF(&bytes.Buffer{}, Document{}, "fancy", DefaultFont()); err == nil {
+		t.Error("RenderPDF() with an unknown template = nil, want an error")
+	}
+}
@@ -0,0 +1,176 @@
+package seed
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+//go:embed fixtures/portfolio.yaml
+var sample embed.FS
+
+// Fixtures is the content of a YAML or JSON fixture file.
+type Fixtures struct {
+	Experiences []ExperienceFixture `json:"experiences" yaml:"experiences"`
+}
+
+type ExperienceFixture struct {
+	Title       string   `json:"title" yaml:"title"`
+	Description []string `json:"description" yaml:"description"`
+	Company     string   `json:"company" yaml:"company"`
+	StartDate   string   `json:"start_date" yaml:"start_date"`
+	EndDate     string   `json:"end_date" yaml:"end_date"`
+}
+
+// Result counts what a seed run did per resource. Skipped counts fixtures
+// whose experience was deleted since it was seeded.
+type Result struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Skipped   int
+	Removed   int
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("created %d, updated %d, unchanged %d, skipped %d, removed %d", r.Created, r.Updated, r.Unchanged, r.Skipped, r.Removed)
+}
+
+// Sample returns the bundled demo dataset.
+func Sample() (*Fixtures, error) {
+	b, err := sample.ReadFile("fixtures/portfolio.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	var f Fixtures
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// Load reads fixtures from a .yaml, .yml or .json file.
+func Load(path string) (*Fixtures, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f Fixtures
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &f)
+	case ".json":
+		err = json.Unmarshal(b, &f)
+	default:
+		return nil, fmt.Errorf("seed: unsupported fixture extension %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("seed: parsing %s: %w", path, err)
+	}
+
+	return &f, nil
+}
+
+// Run loads fixtures through the storage interfaces in a single
+// transaction. Experiences are matched on their natural key (company,
+// title, start date), so running it twice leaves the database unchanged.
+// Experiences that were deleted after seeding stay deleted rather than
+// being seeded again. With reset, every existing experience is permanently
+// removed first.
+func Run(ctx context.Context, s *store.Storage, f *Fixtures, reset bool) (Result, error) {
+	var result Result
+
+	err := s.WithTx(ctx, func(tx *store.Storage) error {
+		var err error
+		result, err = load(ctx, tx, f, reset)
+		return err
+	})
+
+	return result, err
+}
+
+func load(ctx context.Context, s *store.Storage, f *Fixtures, reset bool) (Result, error) {
+	var result Result
+
+	if reset {
+		removed, err := resetExperiences(ctx, s)
+		if err != nil {
+			return result, err
+		}
+		result.Removed = removed
+	}
+
+	for _, fx := range f.Experiences {
+		existing, err := s.Experiences.GetByKey(ctx, fx.Company, fx.Title, fx.StartDate)
+
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			experience := &store.Experience{
+				Title:       fx.Title,
+				Description: fx.Description,
+				Company:     fx.Company,
+				StartDate:   fx.StartDate,
+				EndDate:     fx.EndDate,
+			}
+			if err := s.Experiences.Create(ctx, experience); err != nil {
+				return result, err
+			}
+			result.Created++
+
+		case err != nil:
+			return result, err
+
+		case existing.DeletedAt != nil:
+			result.Skipped++
+
+		case existing.EndDate == fx.EndDate && slices.Equal(existing.Description, fx.Description):
+			result.Unchanged++
+
+		default:
+			existing.Description = fx.Description
+			existing.EndDate = fx.EndDate
+			if err := s.Experiences.Update(ctx, existing); err != nil {
+				return result, err
+			}
+			result.Updated++
+		}
+	}
+
+	return result, nil
+}
+
+func resetExperiences(ctx context.Context, s *store.Storage) (int, error) {
+	live, err := s.Experiences.List(ctx, store.ExperienceFilter{})
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := s.Experiences.ListDeleted(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, experience := range append(live.Data, deleted.Data...) {
+		if err := s.Experiences.HardDelete(ctx, fmt.Sprint(experience.ID)); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,88 @@
+package seed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+// memoryExperiences keeps experiences in memory, matching GetByKey the way
+// the Postgres store does: live rows first, then soft-deleted ones.
+type memoryExperiences struct {
+	store.ExperiencesRepository
+	rows []*store.Experience
+}
+
+func (m *memoryExperiences) GetByKey(ctx context.Context, company, title, startDate string) (*store.Experience, error) {
+	var deleted *store.Experience
+	for _, e := range m.rows {
+		if e.Company != company || e.Title != title || e.StartDate != startDate {
+			continue
+		}
+		if e.DeletedAt == nil {
+			copied := *e
+			return &copied, nil
+		}
+		if deleted == nil {
+			copied := *e
+			deleted = &copied
+		}
+	}
+	if deleted == nil {
+		return nil, store.ErrNotFound
+	}
+	return deleted, nil
+}
+
+func (m *memoryExperiences) Create(ctx context.Context, e *store.Experience) error {
+	e.ID = int64(len(m.rows) + 1)
+	copied := *e
+	m.rows = append(m.rows, &copied)
+	return nil
+}
+
+func (m *memoryExperiences) Update(ctx context.Context, e *store.Experience) error {
+	copied := *e
+	m.rows[e.ID-1] = &copied
+	return nil
+}
+
+func TestRunIsIdempotent(t *testing.T) {
+	experiences := &memoryExperiences{}
+	s := &store.Storage{Experiences: experiences}
+	fixtures := &Fixtures{Experiences: []ExperienceFixture{
+		{Title: "Engineer", Company: "Acme", StartDate: "2020-01", EndDate: "Present", Description: []string{"Built things"}},
+		{Title: "Intern", Company: "Initech", StartDate: "2019-06", EndDate: "2019-09"},
+	}}
+
+	run := func() Result {
+		t.Helper()
+		result, err := Run(context.Background(), s, fixtures, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	if got := run(); got != (Result{Created: 2}) {
+		t.Errorf("first run = %v", got)
+	}
+	if got := run(); got != (Result{Unchanged: 2}) {
+		t.Errorf("second run = %v", got)
+	}
+
+	fixtures.Experiences[0].EndDate = "2024-12"
+	if got := run(); got != (Result{Updated: 1, Unchanged: 1}) {
+		t.Errorf("run after editing a fixture = %v", got)
+	}
+
+	deletedAt := "2025-01-01T00:00:00Z"
+	experiences.rows[1].DeletedAt = &deletedAt
+	if got := run(); got != (Result{Unchanged: 1, Skipped: 1}) {
+		t.Errorf("run after a soft delete = %v", got)
+	}
+	if len(experiences.rows) != 2 {
+		t.Errorf("%d experiences stored, want 2", len(experiences.rows))
+	}
+}
@@ -0,0 +1,73 @@
+package share
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	signer := NewSigner([]byte("secret"))
+	signer.now = func() time.Time { return now }
+
+	valid := signer.Sign(Claims{Subject: "share_links/7", ExpiresAt: now.Add(time.Hour)})
+	encoded, sig, _ := strings.Cut(valid, ".")
+
+	other := NewSigner([]byte("other"))
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{name: "valid", token: valid},
+		{name: "expired", token: signer.Sign(Claims{Subject: "share_links/7", ExpiresAt: now.Add(-time.Second)}), err: ErrExpiredToken},
+		{name: "expires now", token: signer.Sign(Claims{Subject: "share_links/7", ExpiresAt: now}), err: ErrExpiredToken},
+		{name: "other key", token: other.Sign(Claims{Subject: "share_links/7", ExpiresAt: now.Add(time.Hour)}), err: ErrInvalidToken},
+		{name: "tampered signature", token: encoded + "." + flip(sig), err: ErrInvalidToken},
+		{name: "tampered payload", token: flip(encoded) + "." + sig, err: ErrInvalidToken},
+		{name: "extended expiry", token: resign(signer, now.Add(365*24*time.Hour), sig), err: ErrInvalidToken},
+		{name: "no signature", token: encoded, err: ErrInvalidToken},
+		{name: "not base64", token: "!!!.???", err: ErrInvalidToken},
+		{name: "empty", token: "", err: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := signer.Verify(tt.token)
+			if err != tt.err {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && (claims.Subject != "share_links/7" || !claims.ExpiresAt.Equal(now.Add(time.Hour))) {
+				t.Errorf("Verify() = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestVerifyReturnsClaimsOfExpiredToken(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+	expires := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	claims, err := signer.Verify(signer.Sign(Claims{Subject: "share_links/7", ExpiresAt: expires}))
+	if err != ErrExpiredToken || claims.Subject != "share_links/7" || !claims.ExpiresAt.Equal(expires) {
+		t.Errorf("Verify() = %+v, %v", claims, err)
+	}
+}
+
+// flip changes the first character of a base64url string, which unlike
+// the last always changes the decoded bytes.
+func flip(s string) string {
+	if s[0] == 'A' {
+		return "B" + s[1:]
+	}
+	return "A" + s[1:]
+}
+
+// resign puts a payload with a different expiry in front of an existing
+// signature.
+func resign(s *Signer, expires time.Time, sig string) string {
+	encoded, _, _ := strings.Cut(s.Sign(Claims{Subject: "share_links/7", ExpiresAt: expires}), ".")
+	return encoded + "." + sig
+}
@@ -0,0 +1,17 @@
+package store
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor returns a context that attributes store mutations to actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, or "" for anonymous
+// callers.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
@@ -0,0 +1,274 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Analytics event types.
+const (
+	AnalyticsPageView = "page_view"
+	AnalyticsClick    = "click"
+)
+
+// AnalyticsEvent is a single page view or click. Empty strings and a zero
+// ResourceID are stored as NULL.
+type AnalyticsEvent struct {
+	OccurredAt   time.Time
+	Type         string
+	Path         string
+	Referrer     string
+	UTMSource    string
+	UTMMedium    string
+	UTMCampaign  string
+	UTMTerm      string
+	UTMContent   string
+	ResourceType string
+	ResourceID   int64
+	VisitorHash  string
+}
+
+// AnalyticsSummary aggregates events between From (inclusive) and To
+// (exclusive). Visitor hashes rotate daily, so visitors are distinct per
+// day and a visitor returning on another day is counted again.
+type AnalyticsSummary struct {
+	From      time.Time           `json:"from"`
+	To        time.Time           `json:"to"`
+	Days      []AnalyticsDay      `json:"days"`
+	Resources []AnalyticsResource `json:"resources"`
+}
+
+type AnalyticsDay struct {
+	Day       string `json:"day"`
+	PageViews int64  `json:"page_views"`
+	Clicks    int64  `json:"clicks"`
+	Visitors  int64  `json:"visitors"`
+}
+
+type AnalyticsResource struct {
+	ResourceType string `json:"resource_type"`
+	ResourceID   int64  `json:"resource_id"`
+	PageViews    int64  `json:"page_views"`
+	Clicks       int64  `json:"clicks"`
+	Visitors     int64  `json:"visitors"`
+}
+
+type AnalyticsStore struct {
+	db DBTX
+}
+
+// InsertEvents writes events with a single statement.
+func (s *AnalyticsStore) InsertEvents(ctx context.Context, events []*AnalyticsEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO analytics_events (occurred_at, type, path, referrer, utm_source, utm_medium,
+				  utm_campaign, utm_term, utm_content, resource_type, resource_id, visitor_hash)
+			  SELECT occurred_at, type, path, NULLIF(referrer, ''), NULLIF(utm_source, ''), NULLIF(utm_medium, ''),
+				  NULLIF(utm_campaign, ''), NULLIF(utm_term, ''), NULLIF(utm_content, ''),
+				  NULLIF(resource_type, ''), NULLIF(resource_id, 0), visitor_hash
+			  FROM unnest($1::timestamptz[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[],
+				  $7::text[], $8::text[], $9::text[], $10::text[], $11::bigint[], $12::text[])
+				  AS e(occurred_at, type, path, referrer, utm_source, utm_medium,
+				  utm_campaign, utm_term, utm_content, resource_type, resource_id, visitor_hash)`
+
+	columns := make([][]string, 11)
+	resourceIDs := make([]int64, len(events))
+	for i, e := range events {
+		values := []string{
+			e.OccurredAt.UTC().Format(time.RFC3339Nano), e.Type, e.Path, e.Referrer,
+			e.UTMSource, e.UTMMedium, e.UTMCampaign, e.UTMTerm, e.UTMContent,
+			e.ResourceType, e.VisitorHash,
+		}
+		for c, v := range values {
+			columns[c] = append(columns[c], v)
+		}
+		resourceIDs[i] = e.ResourceID
+	}
+
+	args := make([]any, 0, 12)
+	for _, c := range columns[:10] {
+		args = append(args, pq.Array(c))
+	}
+	args = append(args, pq.Array(resourceIDs), pq.Array(columns[10]))
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, args...)
+	return translateError(err)
+}
+
+// DailySalt returns the salt for the UTC day containing t, creating it on
+// first use, and deletes the salts of earlier days. Every replica sees the
+// same salt, so a visitor hashes the same way whichever one they reach.
+func (s *AnalyticsStore) DailySalt(ctx context.Context, t time.Time) ([]byte, error) {
+	day := t.UTC().Format(time.DateOnly)
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, translateError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM analytics_salts WHERE day < $1`, day); err != nil {
+		return nil, translateError(err)
+	}
+
+	query := `WITH inserted AS (
+				  INSERT INTO analytics_salts (day, salt) VALUES ($1, $2)
+				  ON CONFLICT (day) DO NOTHING
+				  RETURNING salt
+			  )
+			  SELECT salt FROM inserted
+			  UNION ALL
+			  SELECT salt FROM analytics_salts WHERE day = $1
+			  LIMIT 1`
+
+	var stored []byte
+	err := s.db.QueryRowContext(ctx, query, day, salt).Scan(&stored)
+	if err == sql.ErrNoRows {
+		// Another replica inserted the salt after this statement's snapshot
+		// was taken; it is visible to a new one.
+		err = s.db.QueryRowContext(ctx, `SELECT salt FROM analytics_salts WHERE day = $1`, day).Scan(&stored)
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return stored, nil
+}
+
+// EnsurePartitions creates the monthly partitions covering the month of t
+// and the following months-1 months. Postgres refuses to create a partition
+// while the default partition holds rows in its range, so any such rows,
+// recorded before the partition existed, are moved into it first: the new
+// partition is created as a plain table, filled, and then attached.
+func (s *AnalyticsStore) EnsurePartitions(ctx context.Context, t time.Time, months int) error {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	for i := 0; i < months; i++ {
+		from, to := start.AddDate(0, i, 0), start.AddDate(0, i+1, 0)
+
+		if err := s.ensurePartition(ctx, from, to); err != nil {
+			return translateError(fmt.Errorf("creating partition for %s: %w", from.Format("2006-01"), err))
+		}
+	}
+
+	return nil
+}
+
+func (s *AnalyticsStore) ensurePartition(ctx context.Context, from, to time.Time) error {
+	name := "analytics_events_" + from.Format("2006_01")
+
+	return transact(ctx, s.db, func(db DBTX) error {
+		// Replicas run this concurrently; the lock is released on commit.
+		if _, err := db.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('analytics_events_partitions'))`); err != nil {
+			return err
+		}
+
+		var exists bool
+		if err := db.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, name).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+
+		table := pq.QuoteIdentifier(name)
+
+		create := fmt.Sprintf(`CREATE TABLE %s (LIKE analytics_events INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`, table)
+		if _, err := db.ExecContext(ctx, create); err != nil {
+			return err
+		}
+
+		move := fmt.Sprintf(`WITH moved AS (
+								 DELETE FROM analytics_events_default
+								 WHERE occurred_at >= $1 AND occurred_at < $2
+								 RETURNING *
+							 )
+							 INSERT INTO %s SELECT * FROM moved`, table)
+		if _, err := db.ExecContext(ctx, move, from, to); err != nil {
+			return err
+		}
+
+		attach := fmt.Sprintf(`ALTER TABLE analytics_events ATTACH PARTITION %s FOR VALUES FROM (%s) TO (%s)`,
+			table, pq.QuoteLiteral(from.Format(time.RFC3339)), pq.QuoteLiteral(to.Format(time.RFC3339)))
+		if _, err := db.ExecContext(ctx, attach); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// Summary aggregates events per UTC day and per resource.
+func (s *AnalyticsStore) Summary(ctx context.Context, from, to time.Time) (*AnalyticsSummary, error) {
+	daysQuery := `SELECT to_char(occurred_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day,
+					  COUNT(*) FILTER (WHERE type = 'page_view'),
+					  COUNT(*) FILTER (WHERE type = 'click'),
+					  COUNT(DISTINCT visitor_hash)
+				  FROM analytics_events WHERE occurred_at >= $1 AND occurred_at < $2
+				  GROUP BY day ORDER BY day`
+	resourcesQuery := `SELECT COALESCE(resource_type, ''), resource_id,
+						   COUNT(*) FILTER (WHERE type = 'page_view') AS page_views,
+						   COUNT(*) FILTER (WHERE type = 'click'),
+						   COUNT(DISTINCT visitor_hash)
+					   FROM analytics_events
+					   WHERE occurred_at >= $1 AND occurred_at < $2 AND resource_id IS NOT NULL
+					   GROUP BY resource_type, resource_id ORDER BY page_views DESC, resource_type, resource_id`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	summary := &AnalyticsSummary{
+		From:      from,
+		To:        to,
+		Days:      []AnalyticsDay{},
+		Resources: []AnalyticsResource{},
+	}
+
+	rows, err := s.db.QueryContext(ctx, daysQuery, from, to)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d AnalyticsDay
+		if err := rows.Scan(&d.Day, &d.PageViews, &d.Clicks, &d.Visitors); err != nil {
+			return nil, translateError(err)
+		}
+		summary.Days = append(summary.Days, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	rows, err = s.db.QueryContext(ctx, resourcesQuery, from, to)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r AnalyticsResource
+		if err := rows.Scan(&r.ResourceType, &r.ResourceID, &r.PageViews, &r.Clicks, &r.Visitors); err != nil {
+			return nil, translateError(err)
+		}
+		summary.Resources = append(summary.Resources, r)
+	}
+
+	return summary, translateError(rows.Err())
+}
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type AuditEvent struct {
+	ID           int64   `json:"id"`
+	OccurredAt   string  `json:"occurred_at"`
+	Actor        *string `json:"actor"`
+	IP           string  `json:"ip"`
+	RequestID    string  `json:"request_id"`
+	Action       string  `json:"action"`
+	ResourceType string  `json:"resource_type"`
+	ResourceID   string  `json:"resource_id"`
+	BeforeHash   *string `json:"before_hash"`
+	AfterHash    *string `json:"after_hash"`
+}
+
+// AuditFilter narrows an audit log listing. Zero values match everything.
+type AuditFilter struct {
+	Actor        string
+	ResourceType string
+	ResourceID   string
+	From         time.Time
+	To           time.Time
+}
+
+type AuditStore struct {
+	db DBTX
+}
+
+func (s *AuditStore) Create(ctx context.Context, event *AuditEvent) error {
+	query := `INSERT INTO audit_events (actor, ip, request_id, action, resource_type, resource_id, before_hash, after_hash) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, occurred_at`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.QueryRowContext(ctx, query,
+		event.Actor, event.IP, event.RequestID, event.Action, event.ResourceType, event.ResourceID,
+		event.BeforeHash, event.AfterHash).Scan(&event.ID, &event.OccurredAt); err != nil {
+		return translateError(err)
+	}
+
+	return nil
+}
+
+// List returns matching events, newest first.
+func (s *AuditStore) List(ctx context.Context, filter AuditFilter, params PaginationParams) (*PaginatedResponse[*AuditEvent], error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Actor != "" {
+		where("actor = $%d", filter.Actor)
+	}
+	if filter.ResourceType != "" {
+		where("resource_type = $%d", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		where("resource_id = $%d", filter.ResourceID)
+	}
+	if !filter.From.IsZero() {
+		where("occurred_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		where("occurred_at < $%d", filter.To)
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_events `+whereClause, args...).Scan(&total); err != nil {
+		return nil, translateError(err)
+	}
+
+	query := fmt.Sprintf(`SELECT id, occurred_at, actor, ip, request_id, action, resource_type, resource_id, before_hash, after_hash 
+						  FROM audit_events %s ORDER BY occurred_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		whereClause, len(args)+1, len(args)+2)
+
+	rows, err := s.db.QueryContext(ctx, query, append(args, params.Limit, params.Offset)...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	events := []*AuditEvent{}
+	for rows.Next() {
+		var e AuditEvent
+		if err := rows.Scan(&e.ID, &e.OccurredAt, &e.Actor, &e.IP, &e.RequestID, &e.Action,
+			&e.ResourceType, &e.ResourceID, &e.BeforeHash, &e.AfterHash); err != nil {
+			return nil, translateError(err)
+		}
+		events = append(events, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return &PaginatedResponse[*AuditEvent]{
+		Data:       events,
+		Pagination: NewPaginationMetadata(params.Limit, params.Offset, total),
+	}, nil
+}
+
+// DeleteBefore expires events older than cutoff and returns how many were
+// removed.
+func (s *AuditStore) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `DELETE FROM audit_events WHERE occurred_at < $1`
+
+	result, err := s.db.ExecContext(ctx, query, cutoff)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/vatanak10/portfolio-backend/internal/cache"
+)
+
+const experiencesGenerationKey = "experiences:generation"
+
+// cachedExperiences decorates an ExperiencesRepository, serving Get and
+// List from a shared cache. Entries are namespaced by a generation counter
+// held in the cache itself; every committed write bumps it, which
+// invalidates all cached experiences on every replica at once. Concurrent
+// misses for the same key are collapsed into a single database query.
+type cachedExperiences struct {
+	ExperiencesRepository
+	cache cache.Cache
+	ttl   time.Duration
+	group singleflight.Group
+}
+
+// EnableExperienceCache puts c in front of Experiences.List and Get. The
+// cache is invalidated from change notifications rather than by wrapping
+// each write, so writes made through WithTx are covered once they commit.
+// Transactional Storages always read from the database.
+func (s *Storage) EnableExperienceCache(c cache.Cache, ttl time.Duration) {
+	cached := &cachedExperiences{
+		ExperiencesRepository: s.Experiences,
+		cache:                 c,
+		ttl:                   ttl,
+	}
+
+	s.Experiences = cached
+	s.OnChange(func(resource string) {
+		if resource == ExperiencesResource || resource == AnyResource {
+			cached.invalidate(context.Background())
+		}
+	})
+}
+
+func (s *cachedExperiences) Get(ctx context.Context, id string) (*Experience, error) {
+	var experience Experience
+	err := s.load(ctx, "get:"+id, &experience, func(ctx context.Context) (any, error) {
+		return s.ExperiencesRepository.Get(ctx, id)
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &experience, nil
+}
+
+func (s *cachedExperiences) List(ctx context.Context, filter ExperienceFilter, params ...PaginationParams) (*PaginatedResponse[*Experience], error) {
+	key := "list:" + filterKey(filter.Visibility) + ":" + filterKey(filter.IDs)
+	if len(params) > 0 {
+		key += fmt.Sprintf(":%d:%d", params[0].Limit, params[0].Offset)
+	}
+
+	var page PaginatedResponse[*Experience]
+	err := s.load(ctx, key, &page, func(ctx context.Context) (any, error) {
+		return s.ExperiencesRepository.List(ctx, filter, params...)
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &page, nil
+}
+
+// filterKey renders a list filter for use in a cache key, telling a nil
+// filter (match everything) apart from an empty one (match nothing).
+func filterKey[T any](values []T) string {
+	if values == nil {
+		return "*"
+	}
+
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, ",")
+}
+
+// load decodes the cached value for key into dst, calling fetch on a miss.
+// Values always round-trip through JSON so that callers never share (and
+// mutate) the same instance. Cache failures degrade to a database read.
+//
+// A fetch is shared by every caller missing the same key, so it runs
+// detached from their contexts: one caller giving up neither cancels it
+// for the others nor stops the rest from waiting for it.
+func (s *cachedExperiences) load(ctx context.Context, key string, dst any, fetch func(context.Context) (any, error)) error {
+	key = "experiences:" + s.generation(ctx) + ":" + key
+
+	if b, ok, err := s.cache.Get(ctx, key); err == nil && ok {
+		return json.Unmarshal(b, dst)
+	}
+
+	fetchCtx := context.WithoutCancel(ctx)
+	result := s.group.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(fetchCtx, QueryTimeoutDuration)
+		defer cancel()
+
+		value, err := fetch(ctx)
+		if err != nil {
+			return nil, translateError(err)
+		}
+
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, translateError(err)
+		}
+
+		s.cache.Set(ctx, key, b, s.ttl)
+
+		return b, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return translateError(ctx.Err())
+	case r := <-result:
+		if r.Err != nil {
+			return translateError(r.Err)
+		}
+		return json.Unmarshal(r.Val.([]byte), dst)
+	}
+}
+
+func (s *cachedExperiences) generation(ctx context.Context) string {
+	b, ok, err := s.cache.Get(ctx, experiencesGenerationKey)
+	if err != nil || !ok {
+		return "0"
+	}
+	return string(b)
+}
+
+// invalidate bumps the generation after a successful write. A failure only
+// leaves entries stale until their TTL expires, so the write still succeeds.
+func (s *cachedExperiences) invalidate(ctx context.Context) {
+	s.cache.Incr(ctx, experiencesGenerationKey)
+}
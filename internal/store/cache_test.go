@@ -0,0 +1,191 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vatanak10/portfolio-backend/internal/cache"
+	"github.com/vatanak10/portfolio-backend/internal/cache/cachetest"
+)
+
+// fakeExperiences serves Get from memory, counting the calls and optionally
+// blocking them until release is closed.
+type fakeExperiences struct {
+	ExperiencesRepository
+
+	calls   atomic.Int32
+	release chan struct{}
+	started chan struct{}
+}
+
+func (f *fakeExperiences) Get(ctx context.Context, id string) (*Experience, error) {
+	f.calls.Add(1)
+	if f.started != nil {
+		f.started <- struct{}{}
+	}
+	if f.release != nil {
+		select {
+		case <-f.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	return &Experience{ID: n, Title: "title " + id}, nil
+}
+
+func cachedStorage(repo ExperiencesRepository, c cache.Cache) *Storage {
+	s := &Storage{Experiences: repo, changes: &changeNotifier{}}
+	s.EnableExperienceCache(c, time.Minute)
+	return s
+}
+
+func TestExperienceCacheServesRepeatedReads(t *testing.T) {
+	repo := &fakeExperiences{}
+	s := cachedStorage(repo, cache.NewMemory(10))
+	ctx := context.Background()
+
+	for range 3 {
+		experience, err := s.Experiences.Get(ctx, "1")
+		if err != nil || experience.Title != "title 1" {
+			t.Fatalf("Get = %+v, %v", experience, err)
+		}
+	}
+
+	if n := repo.calls.Load(); n != 1 {
+		t.Errorf("repository called %d times, want 1", n)
+	}
+}
+
+func TestExperienceCacheInvalidation(t *testing.T) {
+	for _, resource := range []string{ExperiencesResource, AnyResource} {
+		repo := &fakeExperiences{}
+		s := cachedStorage(repo, cache.NewMemory(10))
+		ctx := context.Background()
+
+		s.Experiences.Get(ctx, "1")
+		s.Invalidate(resource)
+		s.Experiences.Get(ctx, "1")
+
+		if n := repo.calls.Load(); n != 2 {
+			t.Errorf("after Invalidate(%q): repository called %d times, want 2", resource, n)
+		}
+	}
+
+	repo := &fakeExperiences{}
+	s := cachedStorage(repo, cache.NewMemory(10))
+	s.Experiences.Get(context.Background(), "1")
+	s.Invalidate("webhooks")
+	s.Experiences.Get(context.Background(), "1")
+
+	if n := repo.calls.Load(); n != 1 {
+		t.Errorf("an unrelated change invalidated the cache: %d calls", n)
+	}
+}
+
+func TestExperienceCacheGenerationIsSharedAcrossReplicas(t *testing.T) {
+	server := cachetest.NewRedis(t)
+	ctx := context.Background()
+
+	replica := func() (*Storage, *fakeExperiences) {
+		c, err := cache.NewRedis(ctx, server.URL(), "test:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { c.Close() })
+
+		repo := &fakeExperiences{}
+		return cachedStorage(repo, c), repo
+	}
+	a, repoA := replica()
+	b, repoB := replica()
+
+	a.Experiences.Get(ctx, "1")
+	b.Experiences.Get(ctx, "1")
+	if n := repoB.calls.Load(); n != 0 {
+		t.Fatalf("replica b missed an entry cached by a (%d calls)", n)
+	}
+
+	// A write on a bumps the generation every replica reads.
+	a.Invalidate(ExperiencesResource)
+	b.Experiences.Get(ctx, "1")
+
+	if n := repoA.calls.Load() + repoB.calls.Load(); n != 2 {
+		t.Errorf("repositories called %d times, want 2", n)
+	}
+}
+
+func TestExperienceCacheCollapsesConcurrentMisses(t *testing.T) {
+	repo := &fakeExperiences{release: make(chan struct{}), started: make(chan struct{}, 10)}
+	s := cachedStorage(repo, cache.NewMemory(10))
+
+	const callers = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.Experiences.Get(context.Background(), "1")
+			errs <- err
+		}()
+	}
+
+	<-repo.started
+	// Let the other callers reach the in-flight fetch.
+	time.Sleep(20 * time.Millisecond)
+	close(repo.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if n := repo.calls.Load(); n != 1 {
+		t.Errorf("repository called %d times, want 1", n)
+	}
+}
+
+func TestExperienceCacheFetchOutlivesCancelledCaller(t *testing.T) {
+	repo := &fakeExperiences{release: make(chan struct{}), started: make(chan struct{}, 10)}
+	s := cachedStorage(repo, cache.NewMemory(10))
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := s.Experiences.Get(firstCtx, "1")
+		first <- err
+	}()
+	<-repo.started
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := s.Experiences.Get(context.Background(), "1")
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancelFirst()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller got %v, want context.Canceled", err)
+	}
+
+	close(repo.release)
+	if err := <-second; err != nil {
+		t.Errorf("waiting caller failed with the first caller's cancellation: %v", err)
+	}
+	if n := repo.calls.Load(); n != 1 {
+		t.Errorf("repository called %d times, want 1", n)
+	}
+}
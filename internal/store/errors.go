@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes translated into domain errors.
+const (
+	pqUniqueViolation           = "23505"
+	pqForeignKeyViolation       = "23503"
+	pqCheckViolation            = "23514"
+	pqInvalidTextRepresentation = "22P02"
+	pqQueryCanceled             = "57014"
+)
+
+// translateError maps database failures onto the store's domain errors, so
+// that callers need not know about Postgres. The original error stays in
+// the chain for logging and for errors.As. Other errors are returned as is.
+func translateError(err error) error {
+	if err == nil || isDomainError(err) {
+		return err
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqUniqueViolation:
+			return fmt.Errorf("%w: %w", ErrConflict, err)
+		case pqForeignKeyViolation:
+			return fmt.Errorf("%w: %w", ErrReferenced, err)
+		case pqCheckViolation, pqInvalidTextRepresentation:
+			return fmt.Errorf("%w: %w", ErrInvalid, err)
+		case pqQueryCanceled:
+			return fmt.Errorf("%w: %w", ErrTimeout, err)
+		}
+		return err
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+
+	return err
+}
+
+// translateIDError is translateError for lookups by an ID given as text: an
+// ID that is not a number matches no row rather than being invalid.
+func translateIDError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pqInvalidTextRepresentation {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return translateError(err)
+}
+
+func isDomainError(err error) bool {
+	for _, target := range []error{ErrNotFound, ErrConflict, ErrReferenced, ErrInvalid, ErrTimeout} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
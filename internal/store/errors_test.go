@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		code pq.ErrorCode
+		want error
+	}{
+		{pqUniqueViolation, ErrConflict},
+		{pqForeignKeyViolation, ErrReferenced},
+		{pqCheckViolation, ErrInvalid},
+		{pqInvalidTextRepresentation, ErrInvalid},
+		{pqQueryCanceled, ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		cause := fmt.Errorf("inserting: %w", &pq.Error{Code: tt.code})
+
+		err := translateError(cause)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("translateError(%s) = %v, want %v", tt.code, err, tt.want)
+		}
+
+		var pqErr *pq.Error
+		if !errors.As(err, &pqErr) {
+			t.Errorf("translateError(%s) lost the *pq.Error", tt.code)
+		}
+
+		if again := translateError(err); again != err {
+			t.Errorf("translateError(%s) is not idempotent: %v", tt.code, again)
+		}
+	}
+}
+
+func TestTranslateErrorPassesThrough(t *testing.T) {
+	for _, err := range []error{nil, sql.ErrNoRows, ErrNotFound, errors.New("boom"), &pq.Error{Code: "42P01"}} {
+		if got := translateError(err); got != err {
+			t.Errorf("translateError(%v) = %v, want it unchanged", err, got)
+		}
+	}
+}
+
+func TestTranslateErrorDeadline(t *testing.T) {
+	err := translateError(context.DeadlineExceeded)
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("translateError(context.DeadlineExceeded) = %v", err)
+	}
+}
+
+func TestTranslateIDError(t *testing.T) {
+	if err := translateIDError(&pq.Error{Code: pqInvalidTextRepresentation}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("invalid ID = %v, want ErrNotFound", err)
+	}
+	if err := translateIDError(&pq.Error{Code: pqQueryCanceled}); !errors.Is(err, ErrTimeout) {
+		t.Errorf("statement timeout = %v, want ErrTimeout", err)
+	}
+}
+
+func TestTranslatedErrorsStayRetryable(t *testing.T) {
+	err := translateError(fmt.Errorf("committing: %w", &pq.Error{Code: "40001"}))
+	if !isRetryable(err) {
+		t.Error("serialization failure is no longer retryable")
+	}
+}
@@ -3,6 +3,11 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
 
 	"github.com/lib/pq"
 )
@@ -14,39 +19,84 @@ type Experience struct {
 	Company     string   `json:"company"`
 	StartDate   string   `json:"start_date"`
 	EndDate     string   `json:"end_date"`
+	Position    int      `json:"position"`
+	Pinned      bool     `json:"pinned"`
+	Visibility  string   `json:"visibility"`
 	CreatedAt   string   `json:"created_at"`
 	UpdatedAt   string   `json:"updated_at"`
 	DeletedAt   *string  `json:"deleted_at,omitempty"`
 }
 
+// Visibility levels. Public experiences are listed for everyone, unlisted
+// ones can only be fetched by ID, and private ones only by authenticated
+// callers or holders of a share token.
+const (
+	VisibilityPublic   = "public"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPrivate  = "private"
+)
+
+// ExperienceFilter narrows List. Nil fields match everything.
+type ExperienceFilter struct {
+	Visibility []string
+	IDs        []int64
+}
+
+// ExperiencesResource identifies experiences in change notifications and
+// polymorphic tables such as translations.
+const ExperiencesResource = "experiences"
+
 type ExperiencesStore struct {
 	// Define methods for the ExperiencesStore
-	db *sql.DB
+	db      DBTX
+	changes changeSink
 }
 
 func (s *ExperiencesStore) Create(ctx context.Context, experience *Experience) error {
+	err := transact(ctx, s.db, func(tx DBTX) error {
+		return createExperience(ctx, tx, experience)
+	})
+	if err != nil {
+		return translateError(err)
+	}
+
+	s.changes.notify(ExperiencesResource)
+
+	return nil
+}
+
+// createExperience inserts an experience and its first revision in tx.
+func createExperience(ctx context.Context, tx DBTX, experience *Experience) error {
+	query := `INSERT INTO experiences (title, description, company, start_date, end_date, pinned, visibility, position) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT COALESCE(MAX(position), 0) + 1 FROM experiences WHERE deleted_at IS NULL))
+			  RETURNING id, position, created_at, updated_at`
 
-	query := `INSERT INTO experiences (title, description, company, start_date, end_date) 
-			  VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at`
+	if experience.Visibility == "" {
+		experience.Visibility = VisibilityPublic
+	}
 
-	err := s.db.QueryRowContext(ctx, query,
+	err := tx.QueryRowContext(ctx, query,
 		experience.Title, pq.Array(experience.Description), experience.Company,
-		experience.StartDate, experience.EndDate).Scan(&experience.ID, &experience.CreatedAt, &experience.UpdatedAt)
+		experience.StartDate, experience.EndDate, experience.Pinned, experience.Visibility).Scan(&experience.ID, &experience.Position, &experience.CreatedAt, &experience.UpdatedAt)
 
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
-	return nil
+	return recordChange(ctx, tx, RevisionCreated, experience)
 }
 
-func (s *ExperiencesStore) List(ctx context.Context, params ...PaginationParams) (*PaginatedResponse[*Experience], error) {
+func (s *ExperiencesStore) List(ctx context.Context, filter ExperienceFilter, params ...PaginationParams) (*PaginatedResponse[*Experience], error) {
+	visibility, ids := pq.Array(filter.Visibility), pq.Array(filter.IDs)
+
 	// First, get the total count (excluding soft-deleted records)
-	countQuery := `SELECT COUNT(*) FROM experiences WHERE deleted_at IS NULL`
+	countQuery := `SELECT COUNT(*) FROM experiences 
+				   WHERE deleted_at IS NULL AND ($1::text[] IS NULL OR visibility = ANY($1))
+				   AND ($2::bigint[] IS NULL OR id = ANY($2))`
 
 	var total int
-	if err := s.db.QueryRowContext(ctx, countQuery).Scan(&total); err != nil {
-		return nil, err
+	if err := s.db.QueryRowContext(ctx, countQuery, visibility, ids).Scan(&total); err != nil {
+		return nil, translateError(err)
 	}
 
 	var query string
@@ -59,22 +109,26 @@ func (s *ExperiencesStore) List(ctx context.Context, params ...PaginationParams)
 		p := params[0]
 		limit = p.Limit
 		offset = p.Offset
-		query = `SELECT id, title, description, company, start_date, end_date, created_at, updated_at 
-				 FROM experiences WHERE deleted_at IS NULL ORDER BY created_at DESC
-				 LIMIT $1 OFFSET $2`
-		args = []interface{}{limit, offset}
+		query = `SELECT id, title, description, company, start_date, end_date, position, pinned, visibility, created_at, updated_at 
+				 FROM experiences WHERE deleted_at IS NULL AND ($1::text[] IS NULL OR visibility = ANY($1))
+				 AND ($2::bigint[] IS NULL OR id = ANY($2))
+				 ORDER BY pinned DESC, position, start_date DESC, id
+				 LIMIT $3 OFFSET $4`
+		args = []interface{}{visibility, ids, limit, offset}
 	} else {
 		// No pagination - return all results
 		limit = total // Use actual total for non-paginated
 		offset = 0
-		query = `SELECT id, title, description, company, start_date, end_date, created_at, updated_at 
-				 FROM experiences WHERE deleted_at IS NULL ORDER BY created_at DESC`
-		args = []interface{}{}
+		query = `SELECT id, title, description, company, start_date, end_date, position, pinned, visibility, created_at, updated_at 
+				 FROM experiences WHERE deleted_at IS NULL AND ($1::text[] IS NULL OR visibility = ANY($1))
+				 AND ($2::bigint[] IS NULL OR id = ANY($2))
+				 ORDER BY pinned DESC, position, start_date DESC, id`
+		args = []interface{}{visibility, ids}
 	}
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	defer rows.Close()
 
@@ -82,15 +136,15 @@ func (s *ExperiencesStore) List(ctx context.Context, params ...PaginationParams)
 	for rows.Next() {
 		var experience Experience
 		if err := rows.Scan(&experience.ID, &experience.Title, pq.Array(&experience.Description),
-			&experience.Company, &experience.StartDate, &experience.EndDate,
+			&experience.Company, &experience.StartDate, &experience.EndDate, &experience.Position, &experience.Pinned, &experience.Visibility,
 			&experience.CreatedAt, &experience.UpdatedAt); err != nil {
-			return nil, err
+			return nil, translateError(err)
 		}
 		experiences = append(experiences, &experience)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
 	// Create pagination metadata
@@ -103,7 +157,7 @@ func (s *ExperiencesStore) List(ctx context.Context, params ...PaginationParams)
 }
 
 func (s *ExperiencesStore) Get(ctx context.Context, id string) (*Experience, error) {
-	query := `SELECT id, title, description, company, start_date, end_date, created_at, updated_at 
+	query := `SELECT id, title, description, company, start_date, end_date, position, pinned, visibility, created_at, updated_at 
 			  FROM experiences WHERE id = $1 AND deleted_at IS NULL`
 
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
@@ -111,115 +165,279 @@ func (s *ExperiencesStore) Get(ctx context.Context, id string) (*Experience, err
 
 	var experience Experience
 	if err := s.db.QueryRowContext(ctx, query, id).Scan(&experience.ID, &experience.Title, pq.Array(&experience.Description),
-		&experience.Company, &experience.StartDate, &experience.EndDate,
+		&experience.Company, &experience.StartDate, &experience.EndDate, &experience.Position, &experience.Pinned, &experience.Visibility,
 		&experience.CreatedAt, &experience.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrNotFound
 		}
-		return nil, err
+		return nil, translateIDError(err)
 	}
 
 	return &experience, nil
 }
 
-func (s *ExperiencesStore) Update(ctx context.Context, experience *Experience) error {
-	query := `UPDATE experiences 
-			  SET title = $1, description = $2, company = $3, start_date = $4, end_date = $5, updated_at = NOW() 
-			  WHERE id = $6 AND deleted_at IS NULL`
+// GetByKey looks up an experience by its natural key, which is how fixtures
+// recognise rows they have already written. Soft-deleted experiences are
+// included, with DeletedAt set, but a live one with the same key wins.
+func (s *ExperiencesStore) GetByKey(ctx context.Context, company, title, startDate string) (*Experience, error) {
+	query := `SELECT id, title, description, company, start_date, end_date, position, pinned, visibility, created_at, updated_at, deleted_at 
+			  FROM experiences WHERE company = $1 AND title = $2 AND start_date = $3
+			  ORDER BY deleted_at IS NOT NULL, id LIMIT 1`
 
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	result, err := s.db.ExecContext(ctx, query,
-		experience.Title, pq.Array(experience.Description), experience.Company,
-		experience.StartDate, experience.EndDate, experience.ID)
-
-	if err != nil {
-		return err
+	var experience Experience
+	if err := s.db.QueryRowContext(ctx, query, company, title, startDate).Scan(&experience.ID, &experience.Title, pq.Array(&experience.Description),
+		&experience.Company, &experience.StartDate, &experience.EndDate, &experience.Position, &experience.Pinned, &experience.Visibility,
+		&experience.CreatedAt, &experience.UpdatedAt, &experience.DeletedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, translateError(err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	return &experience, nil
+}
+
+func (s *ExperiencesStore) Update(ctx context.Context, experience *Experience) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := transact(ctx, s.db, func(tx DBTX) error {
+		return updateExperience(ctx, tx, experience)
+	})
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
-	if rowsAffected == 0 {
-		return ErrNotFound
-	}
+	s.changes.notify(ExperiencesResource)
 
 	return nil
 }
 
+// updateExperience overwrites a live experience and records the revision in tx.
+func updateExperience(ctx context.Context, tx DBTX, experience *Experience) error {
+	query := `UPDATE experiences 
+			  SET title = $1, description = $2, company = $3, start_date = $4, end_date = $5, pinned = $6, visibility = $7, updated_at = NOW() 
+			  WHERE id = $8 AND deleted_at IS NULL
+			  RETURNING position, created_at, updated_at`
+
+	err := tx.QueryRowContext(ctx, query,
+		experience.Title, pq.Array(experience.Description), experience.Company,
+		experience.StartDate, experience.EndDate, experience.Pinned, experience.Visibility, experience.ID).Scan(&experience.Position, &experience.CreatedAt, &experience.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return translateError(err)
+	}
+
+	return recordChange(ctx, tx, RevisionUpdated, experience)
+}
+
 func (s *ExperiencesStore) Delete(ctx context.Context, id string) error {
-	query := `UPDATE experiences SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	return s.setDeleted(ctx, softDeleteQuery, id, RevisionDeleted)
+}
+
+// Restore restores a soft-deleted experience
+func (s *ExperiencesStore) Restore(ctx context.Context, id string) error {
+	return s.setDeleted(ctx, restoreQuery, id, RevisionRestored)
+}
 
+const (
+	softDeleteQuery = `UPDATE experiences SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+					   RETURNING id, title, description, company, start_date, end_date, position, pinned, visibility, created_at, updated_at, deleted_at`
+	restoreQuery = `UPDATE experiences SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL
+					RETURNING id, title, description, company, start_date, end_date, position, pinned, visibility, created_at, updated_at, deleted_at`
+)
+
+// setDeleted runs a soft-delete or restore query returning the affected row
+// and records it as a revision.
+func (s *ExperiencesStore) setDeleted(ctx context.Context, query, id, action string) error {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	result, err := s.db.ExecContext(ctx, query, id)
-
+	err := transact(ctx, s.db, func(tx DBTX) error {
+		_, err := setExperienceDeleted(ctx, tx, query, id, action)
+		return translateIDError(err)
+	})
 	if err != nil {
-		return err
+		return translateIDError(err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
+	s.changes.notify(ExperiencesResource)
+
+	return nil
+}
+
+func setExperienceDeleted(ctx context.Context, tx DBTX, query, id, action string) (*Experience, error) {
+	var experience Experience
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&experience.ID, &experience.Title, pq.Array(&experience.Description),
+		&experience.Company, &experience.StartDate, &experience.EndDate, &experience.Position, &experience.Pinned, &experience.Visibility,
+		&experience.CreatedAt, &experience.UpdatedAt, &experience.DeletedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, translateIDError(err)
 	}
 
-	if rowsAffected == 0 {
-		return ErrNotFound
+	if err := recordChange(ctx, tx, action, &experience); err != nil {
+		return nil, translateIDError(err)
 	}
 
-	return nil
+	return &experience, nil
 }
 
-// Restore restores a soft-deleted experience
-func (s *ExperiencesStore) Restore(ctx context.Context, id string) error {
-	query := `UPDATE experiences SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+// Revert restores the content (and deleted state) captured in a revision
+// and records the result as a new revision. Pinning, display order and
+// visibility are presentation rather than content and are left as they are.
+func (s *ExperiencesStore) Revert(ctx context.Context, id int64, revision int) (*Experience, error) {
+	selectQuery := `SELECT snapshot FROM experience_revisions WHERE experience_id = $1 AND revision = $2`
+	updateQuery := `UPDATE experiences 
+					SET title = $1, description = $2, company = $3, start_date = $4, end_date = $5, 
+						deleted_at = CASE WHEN $6 THEN COALESCE(deleted_at, NOW()) END, updated_at = NOW() 
+					WHERE id = $7
+					RETURNING position, pinned, visibility, created_at, updated_at, deleted_at`
 
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	result, err := s.db.ExecContext(ctx, query, id)
+	var experience Experience
+	err := transact(ctx, s.db, func(tx DBTX) error {
+		var snapshot []byte
+		if err := tx.QueryRowContext(ctx, selectQuery, id, revision).Scan(&snapshot); err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNotFound
+			}
+			return translateError(err)
+		}
+
+		if err := json.Unmarshal(snapshot, &experience); err != nil {
+			return translateError(err)
+		}
+		experience.ID = id
+
+		if err := tx.QueryRowContext(ctx, updateQuery,
+			experience.Title, pq.Array(experience.Description), experience.Company,
+			experience.StartDate, experience.EndDate, experience.DeletedAt != nil, id).Scan(
+			&experience.Position, &experience.Pinned, &experience.Visibility, &experience.CreatedAt, &experience.UpdatedAt, &experience.DeletedAt); err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNotFound
+			}
+			return translateError(err)
+		}
 
+		return recordChange(ctx, tx, RevisionReverted, &experience)
+	})
 	if err != nil {
-		return err
+		return nil, translateError(err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	s.changes.notify(ExperiencesResource)
+
+	return &experience, nil
+}
+
+// Reorder sets the display order of live experiences to follow ids. Any
+// experience not listed keeps its relative order after the listed ones.
+// ErrNotFound is returned, and nothing changed, if an ID does not name a
+// live experience. Every experience that moved is published as updated.
+func (s *ExperiencesStore) Reorder(ctx context.Context, ids []int64) error {
+	countQuery := `SELECT COUNT(*) FROM (
+					   SELECT id FROM experiences WHERE id = ANY($1) AND deleted_at IS NULL FOR UPDATE
+				   ) locked`
+	query := `WITH requested AS (
+				  SELECT id, ord FROM unnest($1::bigint[]) WITH ORDINALITY AS t(id, ord)
+			  ), ranked AS (
+				  SELECT e.id, ROW_NUMBER() OVER (ORDER BY r.ord NULLS LAST, e.position, e.start_date DESC, e.id) AS position
+				  FROM experiences e LEFT JOIN requested r ON r.id = e.id
+				  WHERE e.deleted_at IS NULL
+			  )
+			  UPDATE experiences e SET position = ranked.position
+			  FROM ranked WHERE e.id = ranked.id AND e.position <> ranked.position
+			  RETURNING e.id, e.title, e.description, e.company, e.start_date, e.end_date, e.position, e.pinned, e.visibility, e.created_at, e.updated_at`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := transact(ctx, s.db, func(tx DBTX) error {
+		var found int
+		if err := tx.QueryRowContext(ctx, countQuery, pq.Array(ids)).Scan(&found); err != nil {
+			return translateError(err)
+		}
+		if found != len(ids) {
+			return ErrNotFound
+		}
+
+		rows, err := tx.QueryContext(ctx, query, pq.Array(ids))
+		if err != nil {
+			return translateError(err)
+		}
+		defer rows.Close()
+
+		var moved []*Experience
+		for rows.Next() {
+			var experience Experience
+			if err := rows.Scan(&experience.ID, &experience.Title, pq.Array(&experience.Description),
+				&experience.Company, &experience.StartDate, &experience.EndDate, &experience.Position, &experience.Pinned, &experience.Visibility,
+				&experience.CreatedAt, &experience.UpdatedAt); err != nil {
+				return translateError(err)
+			}
+			moved = append(moved, &experience)
+		}
+		if err := rows.Err(); err != nil {
+			return translateError(err)
+		}
+
+		for _, experience := range moved {
+			if err := publish(ctx, tx, EventExperienceUpdated, ExperiencesResource, experience.ID, experience); err != nil {
+				return translateError(err)
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
-	if rowsAffected == 0 {
-		return ErrNotFound
-	}
+	s.changes.notify(ExperiencesResource)
 
 	return nil
 }
 
-// HardDelete permanently deletes an experience from the database
+// HardDelete permanently deletes an experience and its translations from
+// the database, publishing it as deleted.
 func (s *ExperiencesStore) HardDelete(ctx context.Context, id string) error {
-	query := `DELETE FROM experiences WHERE id = $1`
+	query := `WITH deleted_translations AS (
+				  DELETE FROM translations WHERE resource_type = 'experiences' AND resource_id = $1
+			  )
+			  DELETE FROM experiences WHERE id = $1
+			  RETURNING id, title, description, company, start_date, end_date, position, pinned, visibility, created_at, updated_at, deleted_at`
 
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	result, err := s.db.ExecContext(ctx, query, id)
-
-	if err != nil {
-		return err
-	}
+	err := transact(ctx, s.db, func(tx DBTX) error {
+		var experience Experience
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&experience.ID, &experience.Title, pq.Array(&experience.Description),
+			&experience.Company, &experience.StartDate, &experience.EndDate, &experience.Position, &experience.Pinned, &experience.Visibility,
+			&experience.CreatedAt, &experience.UpdatedAt, &experience.DeletedAt); err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNotFound
+			}
+			return translateIDError(err)
+		}
 
-	rowsAffected, err := result.RowsAffected()
+		return publish(ctx, tx, EventExperienceDeleted, ExperiencesResource, experience.ID, &experience)
+	})
 	if err != nil {
-		return err
+		return translateIDError(err)
 	}
 
-	if rowsAffected == 0 {
-		return ErrNotFound
-	}
+	s.changes.notify(ExperiencesResource)
 
 	return nil
 }
@@ -231,7 +449,7 @@ func (s *ExperiencesStore) ListDeleted(ctx context.Context, params ...Pagination
 
 	var total int
 	if err := s.db.QueryRowContext(ctx, countQuery).Scan(&total); err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
 	var query string
@@ -244,7 +462,7 @@ func (s *ExperiencesStore) ListDeleted(ctx context.Context, params ...Pagination
 		p := params[0]
 		limit = p.Limit
 		offset = p.Offset
-		query = `SELECT id, title, description, company, start_date, end_date, created_at, updated_at, deleted_at 
+		query = `SELECT id, title, description, company, start_date, end_date, position, pinned, visibility, created_at, updated_at, deleted_at 
 				 FROM experiences WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC
 				 LIMIT $1 OFFSET $2`
 		args = []interface{}{limit, offset}
@@ -252,14 +470,14 @@ func (s *ExperiencesStore) ListDeleted(ctx context.Context, params ...Pagination
 		// No pagination - return all results
 		limit = total // Use actual total for non-paginated
 		offset = 0
-		query = `SELECT id, title, description, company, start_date, end_date, created_at, updated_at, deleted_at 
+		query = `SELECT id, title, description, company, start_date, end_date, position, pinned, visibility, created_at, updated_at, deleted_at 
 				 FROM experiences WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC`
 		args = []interface{}{}
 	}
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	defer rows.Close()
 
@@ -267,15 +485,15 @@ func (s *ExperiencesStore) ListDeleted(ctx context.Context, params ...Pagination
 	for rows.Next() {
 		var experience Experience
 		if err := rows.Scan(&experience.ID, &experience.Title, pq.Array(&experience.Description),
-			&experience.Company, &experience.StartDate, &experience.EndDate,
+			&experience.Company, &experience.StartDate, &experience.EndDate, &experience.Position, &experience.Pinned, &experience.Visibility,
 			&experience.CreatedAt, &experience.UpdatedAt, &experience.DeletedAt); err != nil {
-			return nil, err
+			return nil, translateError(err)
 		}
 		experiences = append(experiences, &experience)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
 	// Create pagination metadata
@@ -286,3 +504,257 @@ func (s *ExperiencesStore) ListDeleted(ctx context.Context, params ...Pagination
 		Pagination: metadata,
 	}, nil
 }
+
+// UpsertResult describes what Upsert did, or would do, with one experience.
+type UpsertResult struct {
+	Action     string                 `json:"action"`
+	Experience *Experience            `json:"experience"`
+	Changes    map[string]FieldChange `json:"changes,omitempty"`
+}
+
+// FieldChange holds the old and new value of a changed field.
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+const (
+	UpsertCreated   = "created"
+	UpsertUpdated   = "updated"
+	UpsertUnchanged = "unchanged"
+)
+
+// Upsert creates or updates experiences matched on their natural key in a
+// single transaction. With dryRun nothing is written and the results
+// describe the changes that would be applied.
+func (s *ExperiencesStore) Upsert(ctx context.Context, experiences []*Experience, dryRun bool) ([]UpsertResult, error) {
+	selectQuery := `SELECT id, title, description, company, start_date, end_date, position, pinned, visibility, created_at, updated_at 
+					FROM experiences WHERE company = $1 AND title = $2 AND start_date = $3 AND deleted_at IS NULL
+					ORDER BY id LIMIT 1 FOR UPDATE`
+	updateQuery := `UPDATE experiences SET description = $1, end_date = $2, updated_at = NOW() 
+					WHERE id = $3 RETURNING updated_at`
+
+	results := make([]UpsertResult, 0, len(experiences))
+	err := transact(ctx, s.db, func(tx DBTX) error {
+		for _, experience := range experiences {
+			var existing Experience
+			err := tx.QueryRowContext(ctx, selectQuery, experience.Company, experience.Title, experience.StartDate).Scan(
+				&existing.ID, &existing.Title, pq.Array(&existing.Description),
+				&existing.Company, &existing.StartDate, &existing.EndDate, &existing.Position, &existing.Pinned, &existing.Visibility,
+				&existing.CreatedAt, &existing.UpdatedAt)
+
+			switch {
+			case err == sql.ErrNoRows:
+				if !dryRun {
+					if err := createExperience(ctx, tx, experience); err != nil {
+						return translateError(err)
+					}
+				}
+				results = append(results, UpsertResult{Action: UpsertCreated, Experience: experience})
+				continue
+
+			case err != nil:
+				return translateError(err)
+			}
+
+			changes := map[string]FieldChange{}
+			if !slices.Equal(existing.Description, experience.Description) {
+				changes["description"] = FieldChange{From: existing.Description, To: experience.Description}
+			}
+			if existing.EndDate != experience.EndDate {
+				changes["end_date"] = FieldChange{From: existing.EndDate, To: experience.EndDate}
+			}
+
+			if len(changes) == 0 {
+				results = append(results, UpsertResult{Action: UpsertUnchanged, Experience: &existing})
+				continue
+			}
+
+			existing.Description = experience.Description
+			existing.EndDate = experience.EndDate
+			if !dryRun {
+				if err := tx.QueryRowContext(ctx, updateQuery,
+					pq.Array(existing.Description), existing.EndDate, existing.ID).Scan(&existing.UpdatedAt); err != nil {
+					return translateError(err)
+				}
+				if err := recordChange(ctx, tx, RevisionUpdated, &existing); err != nil {
+					return translateError(err)
+				}
+			}
+			results = append(results, UpsertResult{Action: UpsertUpdated, Experience: &existing, Changes: changes})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	if !dryRun {
+		s.changes.notify(ExperiencesResource)
+	}
+
+	return results, nil
+}
+
+// BatchOperation is a single create, update or delete applied by Batch.
+// Update and delete address the experience by ID; create and update take
+// their fields from Experience. An update changes the pinned flag only
+// when Pinned is set, and keeps the visibility when Experience leaves it
+// empty.
+type BatchOperation struct {
+	Op         string
+	ID         int64
+	Experience *Experience
+	Pinned     *bool
+}
+
+// BatchResult reports the outcome of the operation at Index. Before is the
+// state prior to an update or delete and After the state once applied.
+type BatchResult struct {
+	Index  int
+	Op     string
+	Status string
+	Before *Experience
+	After  *Experience
+	Err    error
+}
+
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+const (
+	BatchApplied    = "applied"
+	BatchFailed     = "failed"
+	BatchRolledBack = "rolled_back"
+	BatchSkipped    = "skipped"
+)
+
+// ErrBatchFailed is returned by an atomic Batch when one of its operations
+// failed and nothing was written; the results say which one.
+var ErrBatchFailed = errors.New("batch operation failed")
+
+// Batch applies ops in order within a single transaction. When atomic, the
+// first failure rolls back the whole batch and ErrBatchFailed is returned
+// alongside the results. Otherwise each operation runs under its own
+// savepoint so that failures are rolled back individually and the rest are
+// committed.
+func (s *ExperiencesStore) Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error) {
+	results := make([]BatchResult, len(ops))
+	for i, op := range ops {
+		results[i] = BatchResult{Index: i, Op: op.Op, Status: BatchSkipped}
+	}
+
+	applied := 0
+	err := transact(ctx, s.db, func(tx DBTX) error {
+		for i, op := range ops {
+			var before, after *Experience
+			apply := func(tx DBTX) (err error) {
+				before, after, err = applyBatchOperation(ctx, tx, op)
+				return translateError(err)
+			}
+
+			var err error
+			if atomic {
+				err = apply(tx)
+			} else {
+				err = transact(ctx, tx, apply)
+			}
+
+			if err != nil {
+				results[i].Status = BatchFailed
+				results[i].Err = err
+
+				if atomic {
+					for j := range results[:i] {
+						results[j].Status = BatchRolledBack
+					}
+					return ErrBatchFailed
+				}
+				continue
+			}
+
+			results[i].Status = BatchApplied
+			results[i].Before = before
+			results[i].After = after
+			applied++
+		}
+
+		return nil
+	})
+	if errors.Is(err, ErrBatchFailed) {
+		return results, translateError(err)
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	if applied > 0 {
+		s.changes.notify(ExperiencesResource)
+	}
+
+	return results, nil
+}
+
+func applyBatchOperation(ctx context.Context, tx DBTX, op BatchOperation) (before, after *Experience, err error) {
+	if op.Op == BatchCreate {
+		experience := *op.Experience
+		if err := createExperience(ctx, tx, &experience); err != nil {
+			return nil, nil, translateError(err)
+		}
+		return nil, &experience, nil
+	}
+
+	before, err = lockExperience(ctx, tx, op.ID)
+	if err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	switch op.Op {
+	case BatchUpdate:
+		experience := *op.Experience
+		experience.ID = op.ID
+		experience.Pinned = before.Pinned
+		if op.Pinned != nil {
+			experience.Pinned = *op.Pinned
+		}
+		if experience.Visibility == "" {
+			experience.Visibility = before.Visibility
+		}
+		if err := updateExperience(ctx, tx, &experience); err != nil {
+			return nil, nil, translateError(err)
+		}
+		return before, &experience, nil
+
+	case BatchDelete:
+		experience, err := setExperienceDeleted(ctx, tx, softDeleteQuery, strconv.FormatInt(op.ID, 10), RevisionDeleted)
+		if err != nil {
+			return nil, nil, translateError(err)
+		}
+		return before, experience, nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown batch operation %q", op.Op)
+}
+
+// lockExperience loads a live experience and locks its row for the rest of tx.
+func lockExperience(ctx context.Context, tx DBTX, id int64) (*Experience, error) {
+	query := `SELECT id, title, description, company, start_date, end_date, position, pinned, visibility, created_at, updated_at 
+			  FROM experiences WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	var experience Experience
+	err := tx.QueryRowContext(ctx, query, id).Scan(&experience.ID, &experience.Title, pq.Array(&experience.Description),
+		&experience.Company, &experience.StartDate, &experience.EndDate, &experience.Position, &experience.Pinned, &experience.Visibility,
+		&experience.CreatedAt, &experience.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, translateError(err)
+	}
+
+	return &experience, nil
+}
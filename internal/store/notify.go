@@ -0,0 +1,60 @@
+package store
+
+import (
+	"slices"
+	"sync"
+)
+
+// changeNotifier fans out "resource changed" signals to in-process
+// listeners such as caches of rendered output.
+type changeNotifier struct {
+	mu        sync.RWMutex
+	listeners []func(resource string)
+}
+
+func (n *changeNotifier) subscribe(fn func(resource string)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.listeners = append(n.listeners, fn)
+}
+
+func (n *changeNotifier) notify(resource string) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	for _, fn := range n.listeners {
+		fn(resource)
+	}
+}
+
+// changeSink receives the change signals raised by a store.
+type changeSink interface {
+	notify(resource string)
+}
+
+// pendingChanges holds back the signals raised inside a transaction until
+// it commits, so listeners never react to changes that were rolled back.
+type pendingChanges struct {
+	mu        sync.Mutex
+	resources []string
+}
+
+func (p *pendingChanges) notify(resource string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !slices.Contains(p.resources, resource) {
+		p.resources = append(p.resources, resource)
+	}
+}
+
+func (p *pendingChanges) flush(to changeSink) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, resource := range p.resources {
+		to.notify(resource)
+	}
+	p.resources = nil
+}
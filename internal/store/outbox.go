@@ -0,0 +1,181 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/vatanak10/portfolio-backend/internal/events"
+)
+
+// Domain events published by the stores. Reverting an experience is
+// published as an update.
+const (
+	EventExperienceCreated  = "experience.created"
+	EventExperienceUpdated  = "experience.updated"
+	EventExperienceDeleted  = "experience.deleted"
+	EventExperienceRestored = "experience.restored"
+)
+
+var eventsByRevision = map[string]string{
+	RevisionCreated:  EventExperienceCreated,
+	RevisionUpdated:  EventExperienceUpdated,
+	RevisionDeleted:  EventExperienceDeleted,
+	RevisionRestored: EventExperienceRestored,
+	RevisionReverted: EventExperienceUpdated,
+}
+
+// ChangesChannel is the Postgres NOTIFY channel every published event is
+// announced on, so that all replicas learn about changes as they commit.
+const ChangesChannel = "content_changes"
+
+// ChangeNotification is the NOTIFY payload announcing an outbox event. It
+// carries no content, which keeps it within the payload size limit.
+type ChangeNotification struct {
+	ID           int64     `json:"id"`
+	Type         string    `json:"type"`
+	ResourceType string    `json:"resource_type"`
+	ResourceID   string    `json:"resource_id"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+// OutboxStore is the events.Source backing the event bus.
+type OutboxStore struct {
+	db DBTX
+}
+
+// recordChange records experience after action as a revision and publishes
+// the matching event, both in tx, so that a change, its history and its
+// announcement are committed together.
+func recordChange(ctx context.Context, tx DBTX, action string, experience *Experience) error {
+	if err := insertRevision(ctx, tx, action, experience); err != nil {
+		return translateError(err)
+	}
+
+	return publish(ctx, tx, eventsByRevision[action], ExperiencesResource, experience.ID, experience)
+}
+
+// publish writes an event to the outbox in tx, attributed to the actor in
+// ctx, and announces it on ChangesChannel. Postgres only delivers the
+// notification if tx commits.
+func publish(ctx context.Context, tx DBTX, eventType, resourceType string, resourceID int64, data any) error {
+	query := `INSERT INTO outbox (type, resource_type, resource_id, payload, actor) VALUES ($1, $2, $3, $4, $5)
+			  RETURNING id, occurred_at`
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return translateError(err)
+	}
+
+	var actor *string
+	if a := ActorFromContext(ctx); a != "" {
+		actor = &a
+	}
+
+	n := ChangeNotification{Type: eventType, ResourceType: resourceType, ResourceID: strconv.FormatInt(resourceID, 10)}
+	if err := tx.QueryRowContext(ctx, query, eventType, resourceType, resourceID, payload, actor).Scan(&n.ID, &n.OccurredAt); err != nil {
+		return translateError(err)
+	}
+
+	notification, err := json.Marshal(n)
+	if err != nil {
+		return translateError(err)
+	}
+
+	_, err = tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, ChangesChannel, string(notification))
+	return translateError(err)
+}
+
+// Claim takes up to limit due events, oldest first, counting the attempt
+// and pushing their next attempt into the future so that no other poller
+// picks them up meanwhile. If the poller dies before completing an event,
+// it is dispatched again once the lease has passed.
+func (s *OutboxStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]events.Event, error) {
+	query := `WITH due AS (
+				  SELECT id FROM outbox
+				  WHERE processed_at IS NULL AND next_attempt_at <= NOW()
+				  ORDER BY id LIMIT $1
+				  FOR UPDATE SKIP LOCKED
+			  )
+			  UPDATE outbox o
+			  SET attempts = o.attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+			  FROM due WHERE o.id = due.id
+			  RETURNING o.id, o.type, o.resource_type, o.resource_id, o.payload, o.actor, o.occurred_at, o.attempts`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	claimed := []events.Event{}
+	for rows.Next() {
+		var e events.Event
+		if err := rows.Scan(&e.ID, &e.Type, &e.ResourceType, &e.ResourceID, &e.Payload, &e.Actor,
+			&e.OccurredAt, &e.Attempt); err != nil {
+			return nil, translateError(err)
+		}
+		claimed = append(claimed, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	// UPDATE ... RETURNING does not keep the order of the CTE.
+	slices.SortFunc(claimed, func(a, b events.Event) int { return cmp.Compare(a.ID, b.ID) })
+
+	return claimed, nil
+}
+
+func (s *OutboxStore) Complete(ctx context.Context, id int64) error {
+	query := `UPDATE outbox SET processed_at = NOW(), last_error = NULL WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, id)
+	return translateError(err)
+}
+
+func (s *OutboxStore) Retry(ctx context.Context, id int64, at time.Time, cause string) error {
+	query := `UPDATE outbox SET next_attempt_at = $1, last_error = $2 WHERE id = $3`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, at, cause, id)
+	return translateError(err)
+}
+
+func (s *OutboxStore) Discard(ctx context.Context, id int64, cause string) error {
+	query := `UPDATE outbox SET processed_at = NOW(), last_error = $1 WHERE id = $2`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, cause, id)
+	return translateError(err)
+}
+
+// Prune removes events processed before the cutoff and returns how many
+// were removed.
+func (s *OutboxStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM outbox WHERE processed_at < $1`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return result.RowsAffected()
+}
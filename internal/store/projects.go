@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+type Project struct {
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	URL         *string `json:"url"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+type ProjectsStore struct {
+	db DBTX
+}
+
+// List returns every project, newest first.
+func (s *ProjectsStore) List(ctx context.Context) ([]*Project, error) {
+	query := `SELECT id, name, description, url, created_at, updated_at FROM projects ORDER BY created_at DESC, id DESC`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	projects := []*Project{}
+	for rows.Next() {
+		var project Project
+		if err := rows.Scan(&project.ID, &project.Name, &project.Description, &project.URL, &project.CreatedAt, &project.UpdatedAt); err != nil {
+			return nil, translateError(err)
+		}
+		projects = append(projects, &project)
+	}
+
+	return projects, translateError(rows.Err())
+}
+
+// ListBySkills returns the projects that used each of several skills in one
+// query, keyed by skill ID and newest first. Skills without projects are
+// absent.
+func (s *ProjectsStore) ListBySkills(ctx context.Context, skillIDs []int64) (map[int64][]*Project, error) {
+	query := `SELECT ps.skill_id, p.id, p.name, p.description, p.url, p.created_at, p.updated_at
+			  FROM project_skills ps JOIN projects p ON p.id = ps.project_id
+			  WHERE ps.skill_id = ANY($1) ORDER BY p.created_at DESC, p.id DESC`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(skillIDs))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	projects := make(map[int64][]*Project, len(skillIDs))
+	for rows.Next() {
+		var (
+			skillID int64
+			project Project
+		)
+		if err := rows.Scan(&skillID, &project.ID, &project.Name, &project.Description, &project.URL, &project.CreatedAt, &project.UpdatedAt); err != nil {
+			return nil, translateError(err)
+		}
+		projects[skillID] = append(projects[skillID], &project)
+	}
+
+	return projects, translateError(rows.Err())
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+)
+
+const (
+	RevisionCreated  = "create"
+	RevisionUpdated  = "update"
+	RevisionDeleted  = "delete"
+	RevisionRestored = "restore"
+	RevisionReverted = "revert"
+)
+
+// Revision is an immutable snapshot of an experience taken after a change.
+type Revision struct {
+	ID           int64      `json:"id"`
+	ExperienceID int64      `json:"experience_id"`
+	Revision     int        `json:"revision"`
+	Action       string     `json:"action"`
+	Snapshot     Experience `json:"snapshot"`
+	Actor        *string    `json:"actor"`
+	CreatedAt    string     `json:"created_at"`
+}
+
+type RevisionsStore struct {
+	db DBTX
+}
+
+// insertRevision records the state of experience after action as the next
+// revision. It runs inside the caller's transaction so that a change and its
+// history are committed together.
+func insertRevision(ctx context.Context, tx DBTX, action string, experience *Experience) error {
+	query := `INSERT INTO experience_revisions (experience_id, revision, action, snapshot, actor)
+			  SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4 
+			  FROM experience_revisions WHERE experience_id = $1`
+
+	snapshot, err := json.Marshal(experience)
+	if err != nil {
+		return translateError(err)
+	}
+
+	var actor *string
+	if a := ActorFromContext(ctx); a != "" {
+		actor = &a
+	}
+
+	_, err = tx.ExecContext(ctx, query, experience.ID, action, snapshot, actor)
+	return translateError(err)
+}
+
+// List returns the revisions of an experience, newest first.
+func (s *RevisionsStore) List(ctx context.Context, experienceID int64) ([]*Revision, error) {
+	query := `SELECT id, experience_id, revision, action, snapshot, actor, created_at 
+			  FROM experience_revisions WHERE experience_id = $1 ORDER BY revision DESC`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, experienceID)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	revisions := []*Revision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, translateError(rows.Err())
+}
+
+func (s *RevisionsStore) Get(ctx context.Context, experienceID int64, revision int) (*Revision, error) {
+	query := `SELECT id, experience_id, revision, action, snapshot, actor, created_at 
+			  FROM experience_revisions WHERE experience_id = $1 AND revision = $2`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	r, err := scanRevision(s.db.QueryRowContext(ctx, query, experienceID, revision))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+
+	return r, translateError(err)
+}
+
+func scanRevision(row interface{ Scan(...any) error }) (*Revision, error) {
+	var (
+		r        Revision
+		snapshot []byte
+	)
+	if err := row.Scan(&r.ID, &r.ExperienceID, &r.Revision, &r.Action, &snapshot, &r.Actor, &r.CreatedAt); err != nil {
+		return nil, translateError(err)
+	}
+
+	if err := json.Unmarshal(snapshot, &r.Snapshot); err != nil {
+		return nil, translateError(err)
+	}
+
+	return &r, nil
+}
+
+// DiffRevisions returns the content fields that differ between two
+// revisions, keyed by JSON field name.
+func DiffRevisions(from, to *Revision) map[string]FieldChange {
+	a, b := from.Snapshot, to.Snapshot
+	changes := map[string]FieldChange{}
+
+	if a.Title != b.Title {
+		changes["title"] = FieldChange{From: a.Title, To: b.Title}
+	}
+	if !slices.Equal(a.Description, b.Description) {
+		changes["description"] = FieldChange{From: a.Description, To: b.Description}
+	}
+	if a.Company != b.Company {
+		changes["company"] = FieldChange{From: a.Company, To: b.Company}
+	}
+	if a.StartDate != b.StartDate {
+		changes["start_date"] = FieldChange{From: a.StartDate, To: b.StartDate}
+	}
+	if a.EndDate != b.EndDate {
+		changes["end_date"] = FieldChange{From: a.EndDate, To: b.EndDate}
+	}
+	if (a.DeletedAt == nil) != (b.DeletedAt == nil) {
+		changes["deleted_at"] = FieldChange{From: a.DeletedAt, To: b.DeletedAt}
+	}
+
+	return changes
+}
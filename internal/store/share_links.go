@@ -0,0 +1,210 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ShareLinksResource identifies share links in the audit log.
+const ShareLinksResource = "share_links"
+
+// Share link scopes: a whole résumé view or a chosen set of experiences.
+const (
+	ShareScopeResume      = "resume"
+	ShareScopeExperiences = "experiences"
+)
+
+// ErrShareLinkUnavailable is returned when a share link has been revoked,
+// has expired or has used up its views.
+var ErrShareLinkUnavailable = errors.New("share link is no longer available")
+
+type ShareLink struct {
+	ID           int64     `json:"id"`
+	Scope        string    `json:"scope"`
+	ResourceIDs  []int64   `json:"resource_ids"`
+	Label        *string   `json:"label"`
+	PasswordHash *string   `json:"-"`
+	MaxUses      *int      `json:"max_uses"`
+	Uses         int       `json:"uses"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RevokedAt    *string   `json:"revoked_at"`
+	CreatedBy    *string   `json:"created_by"`
+	CreatedAt    string    `json:"created_at"`
+}
+
+// ShareLinkView records one successful visit through a share link.
+type ShareLinkView struct {
+	ID        int64  `json:"id"`
+	ViewedAt  string `json:"viewed_at"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+}
+
+type ShareLinksStore struct {
+	db DBTX
+}
+
+// Create stores link, attributing it to the actor in ctx.
+func (s *ShareLinksStore) Create(ctx context.Context, link *ShareLink) error {
+	query := `INSERT INTO share_links (scope, resource_ids, label, password_hash, max_uses, expires_at, created_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, uses, created_at`
+
+	if a := ActorFromContext(ctx); a != "" {
+		link.CreatedBy = &a
+	}
+	if link.ResourceIDs == nil {
+		link.ResourceIDs = []int64{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.QueryRowContext(ctx, query,
+		link.Scope, pq.Array(link.ResourceIDs), link.Label, link.PasswordHash, link.MaxUses,
+		link.ExpiresAt, link.CreatedBy).Scan(&link.ID, &link.Uses, &link.CreatedAt); err != nil {
+		return translateError(err)
+	}
+
+	return nil
+}
+
+// List returns every share link, newest first.
+func (s *ShareLinksStore) List(ctx context.Context) ([]*ShareLink, error) {
+	query := `SELECT id, scope, resource_ids, label, password_hash, max_uses, uses, expires_at, revoked_at, created_by, created_at
+			  FROM share_links ORDER BY created_at DESC, id DESC`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	links := []*ShareLink{}
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		links = append(links, link)
+	}
+
+	return links, translateError(rows.Err())
+}
+
+func (s *ShareLinksStore) Get(ctx context.Context, id int64) (*ShareLink, error) {
+	query := `SELECT id, scope, resource_ids, label, password_hash, max_uses, uses, expires_at, revoked_at, created_by, created_at
+			  FROM share_links WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	link, err := scanShareLink(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, translateError(err)
+	}
+
+	return link, nil
+}
+
+// Revoke disables a link immediately. Revoking it again is ErrNotFound.
+func (s *ShareLinksStore) Revoke(ctx context.Context, id int64) error {
+	query := `UPDATE share_links SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return translateError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// RecordView counts a visit and logs the viewer in one statement, so that
+// concurrent visits cannot exceed the link's maximum uses. It returns
+// ErrShareLinkUnavailable if the link is revoked, expired or used up.
+func (s *ShareLinksStore) RecordView(ctx context.Context, id int64, ip, userAgent string) error {
+	query := `WITH used AS (
+				  UPDATE share_links SET uses = uses + 1
+				  WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+				  AND (max_uses IS NULL OR uses < max_uses)
+				  RETURNING id
+			  )
+			  INSERT INTO share_link_views (share_link_id, ip, user_agent)
+			  SELECT id, $2, $3 FROM used`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, id, ip, userAgent)
+	if err != nil {
+		return translateError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrShareLinkUnavailable
+	}
+
+	return nil
+}
+
+// ListViews returns the visits made through a link, newest first.
+func (s *ShareLinksStore) ListViews(ctx context.Context, id int64) ([]*ShareLinkView, error) {
+	query := `SELECT id, viewed_at, COALESCE(ip, ''), COALESCE(user_agent, '')
+			  FROM share_link_views WHERE share_link_id = $1 ORDER BY viewed_at DESC, id DESC`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	views := []*ShareLinkView{}
+	for rows.Next() {
+		var v ShareLinkView
+		if err := rows.Scan(&v.ID, &v.ViewedAt, &v.IP, &v.UserAgent); err != nil {
+			return nil, translateError(err)
+		}
+		views = append(views, &v)
+	}
+
+	return views, translateError(rows.Err())
+}
+
+func scanShareLink(row interface{ Scan(...any) error }) (*ShareLink, error) {
+	var link ShareLink
+	if err := row.Scan(&link.ID, &link.Scope, pq.Array(&link.ResourceIDs), &link.Label, &link.PasswordHash,
+		&link.MaxUses, &link.Uses, &link.ExpiresAt, &link.RevokedAt, &link.CreatedBy, &link.CreatedAt); err != nil {
+		return nil, translateError(err)
+	}
+
+	return &link, nil
+}
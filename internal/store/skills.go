@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+type Skill struct {
+	ID        int64   `json:"id"`
+	Name      string  `json:"name"`
+	Category  *string `json:"category"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+type SkillsStore struct {
+	db DBTX
+}
+
+// List returns every skill by name.
+func (s *SkillsStore) List(ctx context.Context) ([]*Skill, error) {
+	query := `SELECT id, name, category, created_at, updated_at FROM skills ORDER BY name, id`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	skills := []*Skill{}
+	for rows.Next() {
+		var skill Skill
+		if err := rows.Scan(&skill.ID, &skill.Name, &skill.Category, &skill.CreatedAt, &skill.UpdatedAt); err != nil {
+			return nil, translateError(err)
+		}
+		skills = append(skills, &skill)
+	}
+
+	return skills, translateError(rows.Err())
+}
+
+// ListByExperiences returns the skills of several experiences in one query,
+// keyed by experience ID and ordered by name. Experiences without skills
+// are absent.
+func (s *SkillsStore) ListByExperiences(ctx context.Context, experienceIDs []int64) (map[int64][]*Skill, error) {
+	query := `SELECT es.experience_id, s.id, s.name, s.category, s.created_at, s.updated_at
+			  FROM experience_skills es JOIN skills s ON s.id = es.skill_id
+			  WHERE es.experience_id = ANY($1) ORDER BY s.name, s.id`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(experienceIDs))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	skills := make(map[int64][]*Skill, len(experienceIDs))
+	for rows.Next() {
+		var (
+			experienceID int64
+			skill        Skill
+		)
+		if err := rows.Scan(&experienceID, &skill.ID, &skill.Name, &skill.Category, &skill.CreatedAt, &skill.UpdatedAt); err != nil {
+			return nil, translateError(err)
+		}
+		skills[experienceID] = append(skills[experienceID], &skill)
+	}
+
+	return skills, translateError(rows.Err())
+}
@@ -5,31 +5,168 @@ import (
 	"database/sql"
 	"errors"
 	"time"
+
+	"github.com/vatanak10/portfolio-backend/internal/events"
 )
 
 var (
 	ErrNotFound          = errors.New("resource not found")
 	ErrConflict          = errors.New("resource already exists")
+	ErrReferenced        = errors.New("resource is still referenced")
+	ErrInvalid           = errors.New("resource is invalid")
+	ErrTimeout           = errors.New("query timed out")
 	QueryTimeoutDuration = time.Second * 5
 )
 
+// ExperiencesRepository is implemented by ExperiencesStore and by
+// decorators around it such as the read-through cache.
+type ExperiencesRepository interface {
+	Create(context.Context, *Experience) error
+	List(context.Context, ExperienceFilter, ...PaginationParams) (*PaginatedResponse[*Experience], error)
+	Get(context.Context, string) (*Experience, error)
+	GetByKey(ctx context.Context, company, title, startDate string) (*Experience, error)
+	Update(context.Context, *Experience) error
+	Delete(context.Context, string) error
+	Restore(context.Context, string) error
+	HardDelete(context.Context, string) error
+	ListDeleted(context.Context, ...PaginationParams) (*PaginatedResponse[*Experience], error)
+	Upsert(ctx context.Context, experiences []*Experience, dryRun bool) ([]UpsertResult, error)
+	Revert(ctx context.Context, id int64, revision int) (*Experience, error)
+	Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error)
+	Reorder(ctx context.Context, ids []int64) error
+}
+
 type Storage struct {
-	Experiences interface {
-		Create(context.Context, *Experience) error
-		List(context.Context, ...PaginationParams) (*PaginatedResponse[*Experience], error)
-		Get(context.Context, string) (*Experience, error)
-		Update(context.Context, *Experience) error
-		Delete(context.Context, string) error
-		Restore(context.Context, string) error
-		HardDelete(context.Context, string) error
-		ListDeleted(context.Context, ...PaginationParams) (*PaginatedResponse[*Experience], error)
+	Experiences ExperiencesRepository
+
+	Revisions interface {
+		List(ctx context.Context, experienceID int64) ([]*Revision, error)
+		Get(ctx context.Context, experienceID int64, revision int) (*Revision, error)
+	}
+
+	Translations interface {
+		Upsert(context.Context, *Translation) error
+		Get(ctx context.Context, resourceType string, resourceID int64, locale string) (*Translation, error)
+		List(ctx context.Context, resourceType string, resourceID int64) ([]*Translation, error)
+		ListByResources(ctx context.Context, resourceType string, resourceIDs []int64, locale string) (map[int64]*Translation, error)
+		Delete(ctx context.Context, resourceType string, resourceID int64, locale string) error
+	}
+
+	Skills interface {
+		List(context.Context) ([]*Skill, error)
+		ListByExperiences(ctx context.Context, experienceIDs []int64) (map[int64][]*Skill, error)
+	}
+
+	Projects interface {
+		List(context.Context) ([]*Project, error)
+		ListBySkills(ctx context.Context, skillIDs []int64) (map[int64][]*Project, error)
+	}
+
+	Audit interface {
+		Create(context.Context, *AuditEvent) error
+		List(context.Context, AuditFilter, PaginationParams) (*PaginatedResponse[*AuditEvent], error)
+		DeleteBefore(context.Context, time.Time) (int64, error)
 	}
+
+	ShareLinks interface {
+		Create(context.Context, *ShareLink) error
+		List(context.Context) ([]*ShareLink, error)
+		Get(ctx context.Context, id int64) (*ShareLink, error)
+		Revoke(ctx context.Context, id int64) error
+		RecordView(ctx context.Context, id int64, ip, userAgent string) error
+		ListViews(ctx context.Context, id int64) ([]*ShareLinkView, error)
+	}
+
+	Analytics interface {
+		InsertEvents(context.Context, []*AnalyticsEvent) error
+		DailySalt(context.Context, time.Time) ([]byte, error)
+		EnsurePartitions(ctx context.Context, t time.Time, months int) error
+		Summary(ctx context.Context, from, to time.Time) (*AnalyticsSummary, error)
+	}
+
+	Webhooks interface {
+		Create(context.Context, *Webhook) error
+		List(context.Context) ([]*Webhook, error)
+		Get(ctx context.Context, id int64) (*Webhook, error)
+		Update(context.Context, *Webhook) error
+		Delete(ctx context.Context, id int64) error
+		Enqueue(context.Context, events.Event) error
+		ListDeliveries(ctx context.Context, webhookID int64, params PaginationParams) (*PaginatedResponse[*WebhookDelivery], error)
+		GetDelivery(ctx context.Context, webhookID, id int64) (*WebhookDelivery, error)
+		Redeliver(ctx context.Context, webhookID, id int64) (*WebhookDelivery, error)
+		ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*DueDelivery, error)
+		RecordAttempt(ctx context.Context, attempt *WebhookAttempt, status string, next time.Time) error
+	}
+
+	// Outbox holds the domain events published by the stores' writes.
+	Outbox events.Source
+
+	// db is nil when the Storage is bound to a transaction by WithTx.
+	db      *sql.DB
+	changes *changeNotifier
 }
 
 func NewPostgresStorage(db *sql.DB) *Storage {
+	changes := &changeNotifier{}
+
+	s := newStorage(db, changes, changes)
+	s.db = db
+
+	return s
+}
+
+// newStorage binds every store to db. Stores report their changes to sink,
+// while OnChange subscribes to changes.
+func newStorage(db DBTX, sink changeSink, changes *changeNotifier) *Storage {
 	return &Storage{
 		Experiences: &ExperiencesStore{
+			db:      db,
+			changes: sink,
+		},
+		Revisions: &RevisionsStore{
+			db: db,
+		},
+		Translations: &TranslationsStore{
+			db: db,
+		},
+		Skills: &SkillsStore{
+			db: db,
+		},
+		Projects: &ProjectsStore{
+			db: db,
+		},
+		Audit: &AuditStore{
+			db: db,
+		},
+		ShareLinks: &ShareLinksStore{
 			db: db,
 		},
+		Analytics: &AnalyticsStore{
+			db: db,
+		},
+		Webhooks: &WebhooksStore{
+			db: db,
+		},
+		Outbox: &OutboxStore{
+			db: db,
+		},
+		changes: changes,
 	}
 }
+
+// OnChange registers fn to be called after a store successfully mutates
+// data. The argument names the resource that changed, e.g. "experiences".
+func (s *Storage) OnChange(fn func(resource string)) {
+	s.changes.subscribe(fn)
+}
+
+// AnyResource is passed to OnChange listeners when any resource may have
+// changed, such as after missing change notifications from other replicas.
+const AnyResource = ""
+
+// Invalidate calls the OnChange listeners for resource. It lets changes
+// committed by other replicas reach the same caches as this process's own
+// writes.
+func (s *Storage) Invalidate(resource string) {
+	s.changes.notify(resource)
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/lib/pq"
+)
+
+// Translation holds the localized fields of one resource in one locale.
+// Fields is a JSON object whose keys are the resource's JSON field names.
+type Translation struct {
+	ResourceType string          `json:"resource_type"`
+	ResourceID   int64           `json:"resource_id"`
+	Locale       string          `json:"locale"`
+	Fields       json.RawMessage `json:"fields"`
+	CreatedAt    string          `json:"created_at"`
+	UpdatedAt    string          `json:"updated_at"`
+}
+
+type TranslationsStore struct {
+	db DBTX
+}
+
+// Upsert creates the translation or replaces its fields.
+func (s *TranslationsStore) Upsert(ctx context.Context, t *Translation) error {
+	query := `INSERT INTO translations (resource_type, resource_id, locale, fields) 
+			  VALUES ($1, $2, $3, $4)
+			  ON CONFLICT (resource_type, resource_id, locale) 
+			  DO UPDATE SET fields = EXCLUDED.fields, updated_at = NOW()
+			  RETURNING created_at, updated_at`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.QueryRowContext(ctx, query, t.ResourceType, t.ResourceID, t.Locale, []byte(t.Fields)).
+		Scan(&t.CreatedAt, &t.UpdatedAt); err != nil {
+		return translateError(err)
+	}
+
+	return nil
+}
+
+func (s *TranslationsStore) Get(ctx context.Context, resourceType string, resourceID int64, locale string) (*Translation, error) {
+	query := `SELECT resource_type, resource_id, locale, fields, created_at, updated_at 
+			  FROM translations WHERE resource_type = $1 AND resource_id = $2 AND locale = $3`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var t Translation
+	if err := s.db.QueryRowContext(ctx, query, resourceType, resourceID, locale).Scan(
+		&t.ResourceType, &t.ResourceID, &t.Locale, &t.Fields, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, translateError(err)
+	}
+
+	return &t, nil
+}
+
+// List returns every translation of a resource ordered by locale.
+func (s *TranslationsStore) List(ctx context.Context, resourceType string, resourceID int64) ([]*Translation, error) {
+	query := `SELECT resource_type, resource_id, locale, fields, created_at, updated_at 
+			  FROM translations WHERE resource_type = $1 AND resource_id = $2 ORDER BY locale`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, resourceType, resourceID)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	translations := []*Translation{}
+	for rows.Next() {
+		var t Translation
+		if err := rows.Scan(&t.ResourceType, &t.ResourceID, &t.Locale, &t.Fields, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, translateError(err)
+		}
+		translations = append(translations, &t)
+	}
+
+	return translations, translateError(rows.Err())
+}
+
+// ListByResources returns the translations of several resources in one
+// locale, keyed by resource ID. Resources without one are absent.
+func (s *TranslationsStore) ListByResources(ctx context.Context, resourceType string, resourceIDs []int64, locale string) (map[int64]*Translation, error) {
+	query := `SELECT resource_type, resource_id, locale, fields, created_at, updated_at 
+			  FROM translations WHERE resource_type = $1 AND resource_id = ANY($2) AND locale = $3`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, resourceType, pq.Array(resourceIDs), locale)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	translations := make(map[int64]*Translation, len(resourceIDs))
+	for rows.Next() {
+		var t Translation
+		if err := rows.Scan(&t.ResourceType, &t.ResourceID, &t.Locale, &t.Fields, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, translateError(err)
+		}
+		translations[t.ResourceID] = &t
+	}
+
+	return translations, translateError(rows.Err())
+}
+
+func (s *TranslationsStore) Delete(ctx context.Context, resourceType string, resourceID int64, locale string) error {
+	query := `DELETE FROM translations WHERE resource_type = $1 AND resource_id = $2 AND locale = $3`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, resourceType, resourceID, locale)
+	if err != nil {
+		return translateError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DBTX is the subset of *sql.DB and *sql.Tx used by the stores, so the
+// same store can run standalone or as part of a caller's transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+const (
+	// maxTxAttempts bounds how often WithTx runs fn when the database
+	// aborts the transaction because of a conflict with another one.
+	maxTxAttempts = 3
+	txRetryDelay  = 20 * time.Millisecond
+)
+
+// WithTx runs fn against a Storage whose stores are bound to a single
+// serializable transaction, committing if fn returns nil and rolling back
+// if it returns an error or panics. Serialization failures and deadlocks
+// rerun fn from the start, so fn must not have side effects outside the
+// Storage it is given. Change notifications are delivered once the
+// transaction commits. Calling WithTx on a transactional Storage runs fn
+// within the existing transaction.
+func (s *Storage) WithTx(ctx context.Context, fn func(*Storage) error) error {
+	if s.db == nil {
+		return fn(s)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := s.runTx(ctx, fn)
+		if err == nil || !isRetryable(err) || attempt == maxTxAttempts {
+			return translateError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return translateError(err)
+		case <-time.After(time.Duration(attempt) * txRetryDelay):
+		}
+	}
+}
+
+func (s *Storage) runTx(ctx context.Context, fn func(*Storage) error) (err error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return translateError(err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	pending := &pendingChanges{}
+	if err := fn(newStorage(tx, pending, s.changes)); err != nil {
+		return translateError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return translateError(err)
+	}
+
+	pending.flush(s.changes)
+
+	return nil
+}
+
+// isRetryable reports whether err means the transaction lost a race with a
+// concurrent one and can be safely retried.
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	switch pqErr.Code {
+	case "40001", "40P01": // serialization_failure, deadlock_detected
+		return true
+	}
+
+	return false
+}
+
+// transact runs fn in a transaction of its own when db is a connection pool,
+// or under a savepoint when db is already a transaction, so that a failing
+// store method never leaves the caller's transaction aborted.
+func transact(ctx context.Context, db DBTX, fn func(DBTX) error) error {
+	if beginner, ok := db.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+	}); ok {
+		tx, err := beginner.BeginTx(ctx, nil)
+		if err != nil {
+			return translateError(err)
+		}
+		defer tx.Rollback()
+
+		if err := fn(tx); err != nil {
+			return translateError(err)
+		}
+
+		return tx.Commit()
+	}
+
+	if _, err := db.ExecContext(ctx, "SAVEPOINT store_tx"); err != nil {
+		return translateError(err)
+	}
+
+	if err := fn(db); err != nil {
+		if _, rbErr := db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT store_tx"); rbErr != nil {
+			return translateError(errors.Join(err, rbErr))
+		}
+		return translateError(err)
+	}
+
+	_, err := db.ExecContext(ctx, "RELEASE SAVEPOINT store_tx")
+	return translateError(err)
+}
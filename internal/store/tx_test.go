@@ -0,0 +1,199 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+// fakeDB is a database/sql driver that records the statements it is sent.
+// Commits fail with the errors in commitErrs, in order, and then succeed.
+type fakeDB struct {
+	mu         sync.Mutex
+	log        []string
+	commitErrs []error
+}
+
+func (d *fakeDB) record(statement string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, statement)
+}
+
+func (d *fakeDB) statements() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.log)
+}
+
+func (d *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{d}, nil }
+func (d *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.db.record("BEGIN")
+	return fakeTx{c.db}, nil
+}
+
+func (c fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.record(query)
+	return driver.RowsAffected(0), nil
+}
+
+type fakeTx struct{ db *fakeDB }
+
+func (t fakeTx) Commit() error {
+	t.db.record("COMMIT")
+
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	if len(t.db.commitErrs) == 0 {
+		return nil
+	}
+	err := t.db.commitErrs[0]
+	t.db.commitErrs = t.db.commitErrs[1:]
+	return err
+}
+
+func (t fakeTx) Rollback() error {
+	t.db.record("ROLLBACK")
+	return nil
+}
+
+func newFakeStorage(db *fakeDB) *Storage {
+	return NewPostgresStorage(sql.OpenDB(db))
+}
+
+func TestWithTxRetriesSerializationFailures(t *testing.T) {
+	serializationFailure := &pq.Error{Code: "40001"}
+
+	tests := []struct {
+		name       string
+		commitErrs []error
+		runs       int
+		wantErr    bool
+	}{
+		{name: "commits", runs: 1},
+		{name: "retries", commitErrs: []error{serializationFailure}, runs: 2},
+		{name: "gives up", commitErrs: []error{serializationFailure, serializationFailure, serializationFailure}, runs: maxTxAttempts, wantErr: true},
+		{name: "does not retry other errors", commitErrs: []error{&pq.Error{Code: pqUniqueViolation}}, runs: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{commitErrs: tt.commitErrs}
+			s := newFakeStorage(db)
+
+			runs := 0
+			err := s.WithTx(context.Background(), func(*Storage) error {
+				runs++
+				return nil
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithTx() = %v, want error: %v", err, tt.wantErr)
+			}
+			if runs != tt.runs {
+				t.Errorf("fn ran %d times, want %d", runs, tt.runs)
+			}
+
+			var pqErr *pq.Error
+			if tt.wantErr && !errors.As(err, &pqErr) {
+				t.Errorf("WithTx() = %v, lost the *pq.Error", err)
+			}
+		})
+	}
+}
+
+func TestWithTxRollsBack(t *testing.T) {
+	db := &fakeDB{}
+	s := newFakeStorage(db)
+
+	errFailed := errors.New("failed")
+	if err := s.WithTx(context.Background(), func(*Storage) error { return errFailed }); !errors.Is(err, errFailed) {
+		t.Fatalf("WithTx() = %v, want %v", err, errFailed)
+	}
+
+	if got, want := db.statements(), []string{"BEGIN", "ROLLBACK"}; !slices.Equal(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+}
+
+func TestWithTxRollsBackOnPanic(t *testing.T) {
+	db := &fakeDB{}
+	s := newFakeStorage(db)
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Fatalf("recovered %v, want the original panic", p)
+		}
+		if got, want := db.statements(), []string{"BEGIN", "ROLLBACK"}; !slices.Equal(got, want) {
+			t.Errorf("statements = %q, want %q", got, want)
+		}
+	}()
+
+	s.WithTx(context.Background(), func(*Storage) error { panic("boom") })
+	t.Fatal("WithTx() returned instead of panicking")
+}
+
+func TestTransactUsesSavepointsInsideTransactions(t *testing.T) {
+	db := &fakeDB{}
+	ctx := context.Background()
+
+	tx, err := sql.OpenDB(db).BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errInner := errors.New("inner failed")
+	err = transact(ctx, tx, func(outer DBTX) error {
+		if err := transact(ctx, outer, func(DBTX) error { return errInner }); !errors.Is(err, errInner) {
+			t.Errorf("inner transact() = %v, want %v", err, errInner)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("outer transact() = %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"BEGIN",
+		"SAVEPOINT store_tx",
+		"SAVEPOINT store_tx",
+		"ROLLBACK TO SAVEPOINT store_tx",
+		"RELEASE SAVEPOINT store_tx",
+		"COMMIT",
+	}
+	if got := db.statements(); !slices.Equal(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+}
+
+func TestTransactBeginsOnPool(t *testing.T) {
+	db := &fakeDB{}
+
+	err := transact(context.Background(), sql.OpenDB(db), func(DBTX) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := db.statements(), []string{"BEGIN", "COMMIT"}; !slices.Equal(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,394 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/vatanak10/portfolio-backend/internal/events"
+)
+
+// WebhooksResource identifies webhooks in the audit log.
+const WebhooksResource = "webhooks"
+
+// WebhookEvents lists every event a webhook can subscribe to.
+var WebhookEvents = []string{
+	EventExperienceCreated,
+	EventExperienceUpdated,
+	EventExperienceDeleted,
+	EventExperienceRestored,
+}
+
+// Delivery states. A pending delivery is retried until it succeeds or runs
+// out of attempts and fails.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	ID          int64    `json:"id"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"-"`
+	Events      []string `json:"events"`
+	Description *string  `json:"description"`
+	Active      bool     `json:"active"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// WebhookPayload is the JSON body posted to receivers.
+type WebhookPayload struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// WebhookDelivery is one event queued for one webhook. Log holds its
+// attempts, newest first, when loaded by GetDelivery.
+type WebhookDelivery struct {
+	ID            int64             `json:"id"`
+	WebhookID     int64             `json:"webhook_id"`
+	Event         string            `json:"event"`
+	Payload       json.RawMessage   `json:"payload"`
+	Status        string            `json:"status"`
+	Attempts      int               `json:"attempts"`
+	NextAttemptAt time.Time         `json:"next_attempt_at"`
+	DeliveredAt   *time.Time        `json:"delivered_at"`
+	CreatedAt     string            `json:"created_at"`
+	Log           []*WebhookAttempt `json:"log,omitempty"`
+}
+
+// WebhookAttempt records one HTTP request made for a delivery. StatusCode
+// is nil when no response was received.
+type WebhookAttempt struct {
+	ID          int64   `json:"id"`
+	DeliveryID  int64   `json:"delivery_id"`
+	StatusCode  *int    `json:"status_code"`
+	Error       *string `json:"error"`
+	DurationMS  int64   `json:"duration_ms"`
+	AttemptedAt string  `json:"attempted_at"`
+}
+
+// DueDelivery is a delivery claimed by the worker, with what it needs to
+// send it. Attempt counts this attempt.
+type DueDelivery struct {
+	ID        int64
+	WebhookID int64
+	Event     string
+	Payload   json.RawMessage
+	Attempt   int
+	URL       string
+	Secret    string
+}
+
+type WebhooksStore struct {
+	db DBTX
+}
+
+// Enqueue queues e for every active webhook subscribed to it. It is the
+// event bus subscriber behind webhooks, and queues an event at most once
+// per webhook however often it is dispatched.
+func (s *WebhooksStore) Enqueue(ctx context.Context, e events.Event) error {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event, payload, event_id)
+			  SELECT id, $1::text, $2::jsonb, $3 FROM webhooks WHERE active AND $1::text = ANY(events)
+			  ON CONFLICT (webhook_id, event_id) DO NOTHING`
+
+	payload, err := json.Marshal(WebhookPayload{Event: e.Type, OccurredAt: e.OccurredAt, Data: e.Payload})
+	if err != nil {
+		return translateError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err = s.db.ExecContext(ctx, query, e.Type, payload, e.ID)
+	return translateError(err)
+}
+
+func (s *WebhooksStore) Create(ctx context.Context, webhook *Webhook) error {
+	query := `INSERT INTO webhooks (url, secret, events, description, active)
+			  VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.db.QueryRowContext(ctx, query,
+		webhook.URL, webhook.Secret, pq.Array(webhook.Events), webhook.Description, webhook.Active).Scan(
+		&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt); err != nil {
+		return translateError(err)
+	}
+
+	return nil
+}
+
+func (s *WebhooksStore) List(ctx context.Context) ([]*Webhook, error) {
+	query := `SELECT id, url, secret, events, description, active, created_at, updated_at
+			  FROM webhooks ORDER BY id`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	webhooks := []*Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, translateError(rows.Err())
+}
+
+func (s *WebhooksStore) Get(ctx context.Context, id int64) (*Webhook, error) {
+	query := `SELECT id, url, secret, events, description, active, created_at, updated_at
+			  FROM webhooks WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	webhook, err := scanWebhook(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, translateError(err)
+	}
+
+	return webhook, nil
+}
+
+// Update changes a webhook's URL, events, description and active flag. The
+// secret is never changed.
+func (s *WebhooksStore) Update(ctx context.Context, webhook *Webhook) error {
+	query := `UPDATE webhooks SET url = $1, events = $2, description = $3, active = $4, updated_at = NOW()
+			  WHERE id = $5 RETURNING created_at, updated_at`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.QueryRowContext(ctx, query,
+		webhook.URL, pq.Array(webhook.Events), webhook.Description, webhook.Active, webhook.ID).Scan(
+		&webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return translateError(err)
+	}
+
+	return nil
+}
+
+// Delete removes a webhook together with its deliveries.
+func (s *WebhooksStore) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM webhooks WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return translateError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ListDeliveries returns a webhook's deliveries, newest first.
+func (s *WebhooksStore) ListDeliveries(ctx context.Context, webhookID int64, params PaginationParams) (*PaginatedResponse[*WebhookDelivery], error) {
+	query := `SELECT id, webhook_id, event, payload, status, attempts, next_attempt_at, delivered_at, created_at
+			  FROM webhook_deliveries WHERE webhook_id = $1
+			  ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1`, webhookID).Scan(&total); err != nil {
+		return nil, translateError(err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, webhookID, params.Limit, params.Offset)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	deliveries := []*WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return &PaginatedResponse[*WebhookDelivery]{
+		Data:       deliveries,
+		Pagination: NewPaginationMetadata(params.Limit, params.Offset, total),
+	}, nil
+}
+
+// GetDelivery returns a delivery of webhookID with its attempt log.
+func (s *WebhooksStore) GetDelivery(ctx context.Context, webhookID, id int64) (*WebhookDelivery, error) {
+	query := `SELECT id, webhook_id, event, payload, status, attempts, next_attempt_at, delivered_at, created_at
+			  FROM webhook_deliveries WHERE id = $1 AND webhook_id = $2`
+	attemptsQuery := `SELECT id, delivery_id, status_code, error, duration_ms, attempted_at
+					  FROM webhook_delivery_attempts WHERE delivery_id = $1 ORDER BY attempted_at DESC, id DESC`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	delivery, err := scanWebhookDelivery(s.db.QueryRowContext(ctx, query, id, webhookID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, translateError(err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, attemptsQuery, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	delivery.Log = []*WebhookAttempt{}
+	for rows.Next() {
+		var a WebhookAttempt
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.StatusCode, &a.Error, &a.DurationMS, &a.AttemptedAt); err != nil {
+			return nil, translateError(err)
+		}
+		delivery.Log = append(delivery.Log, &a)
+	}
+
+	return delivery, translateError(rows.Err())
+}
+
+// Redeliver queues a new delivery with the same event and payload as an
+// earlier one. The original and its log are left untouched.
+func (s *WebhooksStore) Redeliver(ctx context.Context, webhookID, id int64) (*WebhookDelivery, error) {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event, payload)
+			  SELECT webhook_id, event, payload FROM webhook_deliveries WHERE id = $1 AND webhook_id = $2
+			  RETURNING id, webhook_id, event, payload, status, attempts, next_attempt_at, delivered_at, created_at`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	delivery, err := scanWebhookDelivery(s.db.QueryRowContext(ctx, query, id, webhookID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, translateError(err)
+	}
+
+	return delivery, nil
+}
+
+// ClaimDue takes up to limit pending deliveries of active webhooks that are
+// due, counting the attempt and pushing their next attempt lease into the
+// future so that no other worker picks them up meanwhile. If the worker
+// dies before recording the outcome, the delivery is retried once the
+// lease has passed.
+func (s *WebhooksStore) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*DueDelivery, error) {
+	query := `WITH due AS (
+				  SELECT d.id FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+				  WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.active
+				  ORDER BY d.next_attempt_at, d.id LIMIT $1
+				  FOR UPDATE OF d SKIP LOCKED
+			  )
+			  UPDATE webhook_deliveries d
+			  SET attempts = d.attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+			  FROM due, webhooks w
+			  WHERE d.id = due.id AND w.id = d.webhook_id
+			  RETURNING d.id, d.webhook_id, d.event, d.payload, d.attempts, w.url, w.secret`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	due := []*DueDelivery{}
+	for rows.Next() {
+		var d DueDelivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Attempt, &d.URL, &d.Secret); err != nil {
+			return nil, translateError(err)
+		}
+		due = append(due, &d)
+	}
+
+	return due, translateError(rows.Err())
+}
+
+// RecordAttempt logs an attempt and moves its delivery to status. A
+// pending delivery is retried at next.
+func (s *WebhooksStore) RecordAttempt(ctx context.Context, attempt *WebhookAttempt, status string, next time.Time) error {
+	insertQuery := `INSERT INTO webhook_delivery_attempts (delivery_id, status_code, error, duration_ms)
+					VALUES ($1, $2, $3, $4) RETURNING id, attempted_at`
+	updateQuery := `UPDATE webhook_deliveries
+					SET status = $1, next_attempt_at = $2,
+						delivered_at = CASE WHEN $1 = 'succeeded' THEN NOW() END
+					WHERE id = $3`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return transact(ctx, s.db, func(tx DBTX) error {
+		if err := tx.QueryRowContext(ctx, insertQuery,
+			attempt.DeliveryID, attempt.StatusCode, attempt.Error, attempt.DurationMS).Scan(&attempt.ID, &attempt.AttemptedAt); err != nil {
+			return translateError(err)
+		}
+
+		_, err := tx.ExecContext(ctx, updateQuery, status, next, attempt.DeliveryID)
+		return translateError(err)
+	})
+}
+
+func scanWebhook(row interface{ Scan(...any) error }) (*Webhook, error) {
+	var w Webhook
+	if err := row.Scan(&w.ID, &w.URL, &w.Secret, pq.Array(&w.Events), &w.Description, &w.Active,
+		&w.CreatedAt, &w.UpdatedAt); err != nil {
+		return nil, translateError(err)
+	}
+
+	return &w, nil
+}
+
+func scanWebhookDelivery(row interface{ Scan(...any) error }) (*WebhookDelivery, error) {
+	var d WebhookDelivery
+	if err := row.Scan(&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.DeliveredAt, &d.CreatedAt); err != nil {
+		return nil, translateError(err)
+	}
+
+	return &d, nil
+}
@@ -0,0 +1,162 @@
+// Package stream fans content change notifications out to long-lived
+// subscribers such as Server-Sent Events connections, keeping a short
+// buffer of recent messages so that reconnecting clients can catch up.
+package stream
+
+import (
+	"slices"
+	"sync"
+)
+
+// subscriberBuffer is how many messages may queue up for a subscriber
+// before it is considered too slow and dropped.
+const subscriberBuffer = 32
+
+// ResetEvent is sent instead of a replay when messages a client asked for
+// are no longer buffered, or may have been missed. Clients should reload
+// their data rather than rely on the stream to be complete.
+const ResetEvent = "reset"
+
+// Message is a single change. IDs are unique but need not arrive in
+// increasing order: concurrent transactions commit, and so notify, in a
+// different order than they were assigned IDs.
+type Message struct {
+	ID    int64
+	Event string
+	Data  []byte
+}
+
+// Hub buffers recent messages in the order it received them and broadcasts
+// new ones to its subscribers.
+type Hub struct {
+	size int
+
+	mu     sync.Mutex
+	buffer []Message
+	// floor is the ID of the last message dropped from the buffer, or 0
+	// when none was since the hub started or was reset. A client that saw
+	// it has missed exactly the buffered messages.
+	floor       int64
+	closed      bool
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives messages on C until it is closed, either by the
+// subscriber or by the hub when the subscriber falls behind or the hub
+// shuts down.
+type Subscription struct {
+	C <-chan Message
+
+	c   chan Message
+	hub *Hub
+}
+
+// NewHub returns a hub buffering the last size messages.
+func NewHub(size int) *Hub {
+	return &Hub{
+		size:        size,
+		buffer:      make([]Message, 0, size),
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Subscribe registers a subscriber that has seen messages up to lastID,
+// or none when lastID is 0. It returns the messages the hub received after
+// lastID and reports whether they are all that were missed. Replay follows
+// arrival order rather than comparing IDs, so a message with a lower ID
+// that arrived later is not skipped. That is only possible when the hub
+// itself received lastID; a hub that has just started, lost its connection
+// or buffered past lastID cannot tell what the client missed.
+func (h *Hub) Subscribe(lastID int64) (*Subscription, []Message, bool) {
+	c := make(chan Message, subscriberBuffer)
+	sub := &Subscription{C: c, c: c, hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(c)
+		return sub, nil, true
+	}
+	h.subscribers[sub] = struct{}{}
+
+	if lastID == 0 {
+		return sub, nil, true
+	}
+
+	if lastID == h.floor {
+		return sub, slices.Clone(h.buffer), true
+	}
+	for i, m := range h.buffer {
+		if m.ID == lastID {
+			return sub, slices.Clone(h.buffer[i+1:]), true
+		}
+	}
+
+	return sub, nil, false
+}
+
+// Close unsubscribes s. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.drop(s)
+}
+
+// Publish buffers m and sends it to every subscriber. Subscribers whose
+// queue is full are dropped; they can reconnect and resume from the buffer.
+func (h *Hub) Publish(m Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.buffer) == h.size {
+		h.floor = h.buffer[0].ID
+		h.buffer = append(h.buffer[:0], h.buffer[1:]...)
+	}
+	h.buffer = append(h.buffer, m)
+
+	h.broadcast(m)
+}
+
+// Reset tells subscribers that messages may have been missed, for example
+// while the connection to the database was down, and stops replaying from
+// before this point.
+func (h *Hub) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.floor = 0
+	h.buffer = h.buffer[:0]
+
+	h.broadcast(Message{Event: ResetEvent})
+}
+
+// Close ends every subscription and refuses new ones, letting long-lived
+// connections finish when the server shuts down.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		h.drop(sub)
+	}
+}
+
+func (h *Hub) broadcast(m Message) {
+	for sub := range h.subscribers {
+		select {
+		case sub.c <- m:
+		default:
+			h.drop(sub)
+		}
+	}
+}
+
+func (h *Hub) drop(sub *Subscription) {
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.c)
+	}
+}
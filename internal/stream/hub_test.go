@@ -0,0 +1,100 @@
+package stream
+
+import (
+	"slices"
+	"testing"
+)
+
+func publishIDs(h *Hub, ids ...int64) {
+	for _, id := range ids {
+		h.Publish(Message{ID: id, Event: "experience.updated"})
+	}
+}
+
+func TestSubscribeReplay(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		published []int64
+		reset     bool
+		lastID    int64
+		replay    int
+		complete  bool
+	}{
+		{name: "new client", size: 4, published: []int64{1, 2}, lastID: 0, replay: 0, complete: true},
+		{name: "fresh hub knows nothing", size: 4, lastID: 7, replay: 0, complete: false},
+		{name: "client ahead of fresh hub", size: 4, published: []int64{10, 11}, lastID: 9, replay: 0, complete: false},
+		{name: "seen by hub", size: 4, published: []int64{10, 11, 12}, lastID: 10, replay: 2, complete: true},
+		{name: "up to date", size: 4, published: []int64{10, 11}, lastID: 11, replay: 0, complete: true},
+		{name: "within floor", size: 2, published: []int64{1, 2, 3, 4}, lastID: 2, replay: 2, complete: true},
+		{name: "below floor", size: 2, published: []int64{1, 2, 3, 4}, lastID: 1, replay: 0, complete: false},
+		{name: "later arrival with a lower ID", size: 4, published: []int64{10, 12, 11}, lastID: 12, replay: 1, complete: true},
+		{name: "dropped later arrival", size: 2, published: []int64{10, 12, 11, 13}, lastID: 12, replay: 2, complete: true},
+		{name: "after reset", size: 2, published: []int64{1, 2, 3}, reset: true, lastID: 3, replay: 0, complete: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHub(tt.size)
+			publishIDs(h, tt.published...)
+			if tt.reset {
+				h.Reset()
+			}
+
+			sub, replay, complete := h.Subscribe(tt.lastID)
+			defer sub.Close()
+
+			if len(replay) != tt.replay || complete != tt.complete {
+				t.Errorf("Subscribe(%d) = %d messages, complete %v; want %d, %v", tt.lastID, len(replay), complete, tt.replay, tt.complete)
+			}
+		})
+	}
+}
+
+func TestResetThenPublish(t *testing.T) {
+	h := NewHub(4)
+	publishIDs(h, 1, 2)
+	h.Reset()
+	publishIDs(h, 5, 6)
+
+	// 3 and 4 may have been sent while the hub was disconnected.
+	if _, _, complete := h.Subscribe(2); complete {
+		t.Error("Subscribe(2) reported a complete replay across a reset")
+	}
+	if _, replay, complete := h.Subscribe(5); !complete || len(replay) != 1 {
+		t.Errorf("Subscribe(5) = %d messages, complete %v; want 1, true", len(replay), complete)
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	h := NewHub(1)
+	sub, _, _ := h.Subscribe(0)
+
+	for i := int64(1); i <= subscriberBuffer+1; i++ {
+		h.Publish(Message{ID: i})
+	}
+
+	n := 0
+	for range sub.C {
+		n++
+	}
+	if n != subscriberBuffer {
+		t.Errorf("received %d messages before being dropped, want %d", n, subscriberBuffer)
+	}
+}
+
+func TestReplayFollowsArrivalOrder(t *testing.T) {
+	h := NewHub(4)
+	// 11 committed after 12, so it was notified after it.
+	publishIDs(h, 10, 12, 11, 13)
+
+	_, replay, complete := h.Subscribe(12)
+
+	var ids []int64
+	for _, m := range replay {
+		ids = append(ids, m.ID)
+	}
+	if !complete || !slices.Equal(ids, []int64{11, 13}) {
+		t.Errorf("Subscribe(12) = %v, complete %v; want [11 13], true", ids, complete)
+	}
+}
@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+const (
+	minReconnectInterval = 10 * time.Second
+	maxReconnectInterval = time.Minute
+	// pingInterval is how often an idle listener checks its connection.
+	pingInterval = 90 * time.Second
+)
+
+// Listen subscribes to store.ChangesChannel on a dedicated connection to
+// the database at dsn and publishes every notification to hub until ctx is
+// cancelled. Unlike outbox events, which one replica claims, notifications
+// reach every replica, so onChange is called with the changed resource to
+// let each invalidate its own caches. The connection is re-established when
+// lost, after which the hub is reset and onChange is called with
+// store.AnyResource because notifications sent meanwhile are gone.
+func Listen(ctx context.Context, dsn string, hub *Hub, onChange func(resource string), logger *zap.SugaredLogger) error {
+	listener := pq.NewListener(dsn, minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Warnw("change listener connection", "event", event, "error", err.Error())
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(store.ChangesChannel); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case n := <-listener.Notify:
+			// A nil notification follows a reconnect.
+			if n == nil {
+				onChange(store.AnyResource)
+				hub.Reset()
+				continue
+			}
+
+			var change store.ChangeNotification
+			if err := json.Unmarshal([]byte(n.Extra), &change); err != nil {
+				logger.Warnw("malformed change notification", "payload", n.Extra, "error", err.Error())
+				continue
+			}
+
+			onChange(change.ResourceType)
+			hub.Publish(Message{ID: change.ID, Event: change.Type, Data: []byte(n.Extra)})
+
+		case <-ticker.C:
+			if err := listener.Ping(); err != nil {
+				logger.Warnw("pinging change listener", "error", err.Error())
+			}
+		}
+	}
+}
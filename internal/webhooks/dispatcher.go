@@ -0,0 +1,188 @@
+// Package webhooks delivers queued content change events to registered
+// endpoints, signing each request and retrying failures with exponential
+// backoff.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+const (
+	// claimBatchSize bounds how many deliveries are sent concurrently.
+	claimBatchSize = 20
+	// maxBackoff caps the delay between two attempts.
+	maxBackoff = 6 * time.Hour
+	// maxResponseBody is how much of a response is read before the
+	// connection is reused.
+	maxResponseBody = 64 << 10
+)
+
+// Source is where deliveries are claimed from and their outcome recorded.
+type Source interface {
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*store.DueDelivery, error)
+	RecordAttempt(ctx context.Context, attempt *store.WebhookAttempt, status string, next time.Time) error
+}
+
+// Config tunes a Dispatcher.
+type Config struct {
+	// PollInterval is how often the queue is checked for due deliveries.
+	PollInterval time.Duration
+	// Timeout bounds a single request to a receiver.
+	Timeout time.Duration
+	// MaxAttempts is how often a delivery is tried before it fails.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It doubles with every
+	// further attempt.
+	Backoff time.Duration
+}
+
+// Dispatcher sends due deliveries. Any response in the 2xx range counts as
+// success; anything else, including a timeout, is retried.
+type Dispatcher struct {
+	source Source
+	logger *zap.SugaredLogger
+	config Config
+	client *http.Client
+}
+
+func NewDispatcher(source Source, logger *zap.SugaredLogger, config Config) *Dispatcher {
+	return &Dispatcher{
+		source: source,
+		logger: logger,
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Run delivers webhooks until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while there is a full backlog.
+		for d.dispatch(ctx) == claimBatchSize {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch sends one batch of due deliveries and returns its size.
+func (d *Dispatcher) dispatch(ctx context.Context) int {
+	// A claimed delivery is not handed out again until the lease passes,
+	// which must outlast the request and recording its outcome.
+	lease := 2*d.config.Timeout + store.QueryTimeoutDuration
+
+	due, err := d.source.ClaimDue(ctx, claimBatchSize, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			d.logger.Errorw("claiming webhook deliveries", "error", err.Error())
+		}
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+
+	return len(due)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *store.DueDelivery) {
+	start := time.Now()
+	statusCode, err := d.send(ctx, delivery)
+
+	attempt := &store.WebhookAttempt{
+		DeliveryID: delivery.ID,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if statusCode != 0 {
+		attempt.StatusCode = &statusCode
+	}
+
+	status, next := store.DeliverySucceeded, time.Now()
+	if err != nil {
+		message := err.Error()
+		attempt.Error = &message
+
+		status, next = store.DeliveryPending, time.Now().Add(d.backoff(delivery.Attempt))
+		if delivery.Attempt >= d.config.MaxAttempts {
+			status = store.DeliveryFailed
+		}
+
+		d.logger.Warnw("webhook delivery failed",
+			"delivery", delivery.ID, "webhook", delivery.WebhookID, "attempt", delivery.Attempt, "status", status, "error", message)
+	}
+
+	// Record the outcome even if ctx was cancelled mid-request, so that the
+	// attempt is not lost on shutdown.
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), store.QueryTimeoutDuration)
+	defer cancel()
+
+	if err := d.source.RecordAttempt(recordCtx, attempt, status, next); err != nil {
+		d.logger.Errorw("recording webhook attempt", "delivery", delivery.ID, "error", err.Error())
+	}
+}
+
+// send posts the payload and returns the response status code, which is 0
+// when no response was received.
+func (d *Dispatcher) send(ctx context.Context, delivery *store.DueDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "portfolio-webhooks/1")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, now, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay after the given attempt: Backoff, then twice
+// that, and so on up to maxBackoff.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.config.Backoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
@@ -0,0 +1,155 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/vatanak10/portfolio-backend/internal/store"
+)
+
+const testSecret = "s3cret"
+
+// recordingSource hands out fixed deliveries once and records the outcome
+// of each attempt.
+type recordingSource struct {
+	mu       sync.Mutex
+	due      []*store.DueDelivery
+	recorded []recordedAttempt
+}
+
+type recordedAttempt struct {
+	attempt *store.WebhookAttempt
+	status  string
+	next    time.Time
+}
+
+func (s *recordingSource) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*store.DueDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	due := s.due
+	s.due = nil
+	return due, nil
+}
+
+func (s *recordingSource) RecordAttempt(ctx context.Context, attempt *store.WebhookAttempt, status string, next time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recorded = append(s.recorded, recordedAttempt{attempt, status, next})
+	return nil
+}
+
+// receiver starts a server that verifies each request's signature and
+// answers with status.
+func receiver(t *testing.T, status int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := Verify(testSecret, r.Header.Get(HeaderSignature), r.Header.Get(HeaderTimestamp), body, time.Minute); err != nil {
+			t.Errorf("receiver: %v", err)
+		}
+		if r.Header.Get(HeaderEvent) != store.EventExperienceUpdated || r.Header.Get(HeaderDelivery) != "7" {
+			t.Errorf("receiver: headers %v", r.Header)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDeliver(t *testing.T) {
+	config := Config{Timeout: time.Second, MaxAttempts: 3, Backoff: time.Minute}
+
+	tests := []struct {
+		name       string
+		status     int
+		attempt    int
+		wantStatus string
+		wantDelay  time.Duration
+	}{
+		{name: "success", status: http.StatusNoContent, attempt: 1, wantStatus: store.DeliverySucceeded},
+		{name: "retried", status: http.StatusInternalServerError, attempt: 1, wantStatus: store.DeliveryPending, wantDelay: time.Minute},
+		{name: "backs off", status: http.StatusBadGateway, attempt: 2, wantStatus: store.DeliveryPending, wantDelay: 2 * time.Minute},
+		{name: "gives up", status: http.StatusInternalServerError, attempt: 3, wantStatus: store.DeliveryFailed, wantDelay: 4 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := receiver(t, tt.status)
+			source := &recordingSource{due: []*store.DueDelivery{{
+				ID:        7,
+				WebhookID: 1,
+				Event:     store.EventExperienceUpdated,
+				Payload:   []byte(`{"id":1}`),
+				Attempt:   tt.attempt,
+				URL:       srv.URL,
+				Secret:    testSecret,
+			}}}
+
+			start := time.Now()
+			if n := NewDispatcher(source, zap.NewNop().Sugar(), config).dispatch(context.Background()); n != 1 {
+				t.Fatalf("dispatch() = %d, want 1", n)
+			}
+
+			if len(source.recorded) != 1 {
+				t.Fatalf("recorded %d attempts, want 1", len(source.recorded))
+			}
+			got := source.recorded[0]
+
+			if got.status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got.status, tt.wantStatus)
+			}
+			if got.attempt.DeliveryID != 7 || got.attempt.StatusCode == nil || *got.attempt.StatusCode != tt.status {
+				t.Errorf("attempt = %+v, want delivery 7 with status code %d", got.attempt, tt.status)
+			}
+			if (got.attempt.Error != nil) != (tt.wantStatus != store.DeliverySucceeded) {
+				t.Errorf("attempt error = %v", got.attempt.Error)
+			}
+			if delay := got.next.Sub(start); delay < tt.wantDelay || delay > tt.wantDelay+time.Second {
+				t.Errorf("next attempt in %v, want %v", delay, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestDeliverUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	source := &recordingSource{due: []*store.DueDelivery{{ID: 7, Attempt: 1, URL: srv.URL, Secret: testSecret}}}
+	NewDispatcher(source, zap.NewNop().Sugar(), Config{Timeout: time.Second, MaxAttempts: 3, Backoff: time.Minute}).dispatch(context.Background())
+
+	got := source.recorded[0]
+	if got.status != store.DeliveryPending || got.attempt.StatusCode != nil || got.attempt.Error == nil {
+		t.Errorf("recorded %q %+v, want a pending attempt with an error and no status code", got.status, got.attempt)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := &Dispatcher{config: Config{Backoff: 30 * time.Second}}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, maxBackoff},
+		{100, maxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers set on every delivery.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const signaturePrefix = "sha256="
+
+var ErrInvalidSignature = errors.New("webhooks: invalid signature")
+
+// Sign returns the signature header value for body sent at timestamp: the
+// hex HMAC-SHA256, keyed with the webhook secret, of "<unix timestamp>.<body>".
+// Including the timestamp lets receivers reject replayed requests.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	return signaturePrefix + hex.EncodeToString(mac(secret, strconv.FormatInt(timestamp.Unix(), 10), body))
+}
+
+// Verify checks the signature and timestamp headers of a received delivery
+// against its body, rejecting timestamps more than tolerance away from now.
+// Receivers written in Go can use it directly.
+func Verify(secret, signature, timestamp string, body []byte, tolerance time.Duration) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	if d := time.Since(time.Unix(unix, 0)); d > tolerance || d < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil || !hmac.Equal(got, mac(secret, timestamp, body)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func mac(secret, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	const secret = "s3cret"
+	body := []byte(`{"event":"experience.created"}`)
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		timestamp string
+		body      []byte
+		valid     bool
+	}{
+		{name: "valid", secret: secret, signature: Sign(secret, now, body), timestamp: unix(now), body: body, valid: true},
+		{name: "within tolerance", secret: secret, signature: Sign(secret, now.Add(-4*time.Minute), body), timestamp: unix(now.Add(-4 * time.Minute)), body: body, valid: true},
+		{name: "tampered body", secret: secret, signature: Sign(secret, now, body), timestamp: unix(now), body: []byte(`{"event":"experience.deleted"}`)},
+		{name: "wrong secret", secret: "other", signature: Sign(secret, now, body), timestamp: unix(now), body: body},
+		{name: "timestamp swapped", secret: secret, signature: Sign(secret, now, body), timestamp: unix(now.Add(time.Second)), body: body},
+		{name: "stale", secret: secret, signature: Sign(secret, now.Add(-time.Hour), body), timestamp: unix(now.Add(-time.Hour)), body: body},
+		{name: "future", secret: secret, signature: Sign(secret, now.Add(time.Hour), body), timestamp: unix(now.Add(time.Hour)), body: body},
+		{name: "malformed timestamp", secret: secret, signature: Sign(secret, now, body), timestamp: "yesterday", body: body},
+		{name: "malformed signature", secret: secret, signature: "sha256=not-hex", timestamp: unix(now), body: body},
+		{name: "empty signature", secret: secret, timestamp: unix(now), body: body},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.signature, tt.timestamp, tt.body, 5*time.Minute)
+			if tt.valid && err != nil {
+				t.Errorf("Verify() = %v, want nil", err)
+			}
+			if !tt.valid && err != ErrInvalidSignature {
+				t.Errorf("Verify() = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func unix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}